@@ -14,6 +14,7 @@ type Camera2D struct {
 	height             float32
 	halfHeight         float32
 	zoom               float32
+	rotation           float32
 	minZoom            float32
 	maxZoom            float32
 	centered           bool
@@ -53,6 +54,12 @@ func (c *Camera2D) ProjectionMatrix() *mgl32.Mat4 {
 	return &c.projectionMatrix
 }
 
+// ViewProjection returns the combined view (position, zoom and rotation) and projection matrix of the camera
+func (c *Camera2D) ViewProjection() mgl32.Mat4 {
+	c.rebuildMatrix()
+	return c.projectionMatrix
+}
+
 // SetPosition sets the current position of the camera. If the camera is centered, the center will be moving
 func (c *Camera2D) SetPosition(x float32, y float32) {
 	c.x = x
@@ -80,6 +87,15 @@ func (c *Camera2D) SetZoom(zoom float32) {
 	c.matrixDirty = true
 }
 
+// Rotation returns the rotation of the camera in radians
+func (c *Camera2D) Rotation() float32 { return c.rotation }
+
+// SetRotation rotates the camera around the center of the visible area. The world appears rotated the opposite way
+func (c *Camera2D) SetRotation(radians float32) {
+	c.rotation = radians
+	c.matrixDirty = true
+}
+
 // MinZoom returns the minimum zoom level allowed
 func (c *Camera2D) MinZoom() float32 { return c.minZoom }
 
@@ -151,11 +167,20 @@ func (c *Camera2D) rebuildMatrix() {
 	}
 
 	c.projectionMatrix = mgl32.Ortho(left, right, top, bottom, c.near, c.far)
+	if c.rotation != 0 {
+		centerX := (left + right) / 2
+		centerY := (top + bottom) / 2
+		c.projectionMatrix = c.projectionMatrix.
+			Mul4(mgl32.Translate3D(centerX, centerY, 0)).
+			Mul4(mgl32.HomogRotate3DZ(-c.rotation)).
+			Mul4(mgl32.Translate3D(-centerX, -centerY, 0))
+	}
 	c.inverseMatrix = c.projectionMatrix.Inv()
 	c.matrixDirty = false
 }
 
 func (c *Camera2D) ScreenToWorld(vec mgl32.Vec2) mgl32.Vec3 {
+	c.rebuildMatrix()
 	if c.flipVertical {
 		vec[1] = c.height - vec[1]
 	}
@@ -165,6 +190,7 @@ func (c *Camera2D) ScreenToWorld(vec mgl32.Vec2) mgl32.Vec3 {
 }
 
 func (c *Camera2D) WorldToScreen(vec mgl32.Vec3) mgl32.Vec2 {
+	c.rebuildMatrix()
 	ret := mgl32.TransformCoordinate(vec, c.projectionMatrix)
 	ret[0] = ret[0]*c.halfWidth + c.halfWidth
 	ret[1] = ret[1]*c.halfHeight + c.halfHeight
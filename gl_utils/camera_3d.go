@@ -0,0 +1,82 @@
+package gl_utils
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Camera3D a Camera based on a perspective projection looking from Position to Target.
+// The state is kept in plain fields: the caller moves the camera, the camera only builds the matrices
+type Camera3D struct {
+	Position mgl32.Vec3
+	Target   mgl32.Vec3
+	// WorldUp is the direction considered up, used to orient the camera around the view direction
+	WorldUp mgl32.Vec3
+	// FOV is the vertical field of view in radians
+	FOV    float32
+	Aspect float32
+	Near   float32
+	Far    float32
+}
+
+// NewCamera3D sets up a perspective camera with +Y as up direction and the clip planes at 0.1 and 1000
+func NewCamera3D(position mgl32.Vec3, target mgl32.Vec3, fov float32, aspect float32) *Camera3D {
+	return &Camera3D{
+		Position: position,
+		Target:   target,
+		WorldUp:  mgl32.Vec3{0, 1, 0},
+		FOV:      fov,
+		Aspect:   aspect,
+		Near:     0.1,
+		Far:      1000,
+	}
+}
+
+// View returns the view matrix of the camera
+func (c *Camera3D) View() mgl32.Mat4 {
+	return mgl32.LookAtV(c.Position, c.Target, c.WorldUp)
+}
+
+// Projection returns the perspective projection matrix of the camera
+func (c *Camera3D) Projection() mgl32.Mat4 {
+	return mgl32.Perspective(c.FOV, c.Aspect, c.Near, c.Far)
+}
+
+// ViewProjection returns the projection matrix multiplied by the view matrix
+func (c *Camera3D) ViewProjection() mgl32.Mat4 {
+	return c.Projection().Mul4(c.View())
+}
+
+// Forward returns the normalized direction the camera is looking at
+func (c *Camera3D) Forward() mgl32.Vec3 {
+	return c.Target.Sub(c.Position).Normalize()
+}
+
+// Right returns the normalized direction pointing to the right of the camera
+func (c *Camera3D) Right() mgl32.Vec3 {
+	return c.Forward().Cross(c.WorldUp).Normalize()
+}
+
+// Up returns the normalized up direction of the camera, perpendicular to Forward and Right
+func (c *Camera3D) Up() mgl32.Vec3 {
+	return c.Right().Cross(c.Forward())
+}
+
+// ScreenToRay unprojects a point of the screen into a world space ray, for mouse picking (e.g. with RayAABB).
+// screen and viewport (x, y, width, height) are in pixels with the origin at the top-left corner of the window, like
+// the mouse coordinates. A viewport in the bottom-left convention of gl.Viewport has y = windowHeight-y-height.
+// The origin of the ray is on the near plane, dir is normalized
+func ScreenToRay(screen mgl32.Vec2, viewport mgl32.Vec4, view, proj mgl32.Mat4) (origin, dir mgl32.Vec3) {
+	ndcX := (screen.X()-viewport[0])/viewport[2]*2 - 1
+	// Normalized device coordinates have +Y going up
+	ndcY := 1 - (screen.Y()-viewport[1])/viewport[3]*2
+	inverse := proj.Mul4(view).Inv()
+	near := inverse.Mul4x1(mgl32.Vec4{ndcX, ndcY, -1, 1})
+	far := inverse.Mul4x1(mgl32.Vec4{ndcX, ndcY, 1, 1})
+	origin = near.Vec3().Mul(1 / near.W())
+	dir = far.Vec3().Mul(1 / far.W()).Sub(origin).Normalize()
+	return origin, dir
+}
+
+// ScreenToRay unprojects a point of the screen into a world space ray using the matrices of the camera, see the
+// ScreenToRay function for the conventions
+func (c *Camera3D) ScreenToRay(screen mgl32.Vec2, viewport mgl32.Vec4) (origin, dir mgl32.Vec3) {
+	return ScreenToRay(screen, viewport, c.View(), c.Projection())
+}
@@ -1,6 +1,13 @@
 package gl_utils
 
-import "github.com/go-gl/mathgl/mgl32"
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
 
 // Color is a Vec4
 type Color mgl32.Vec4
@@ -37,3 +44,181 @@ func (c *Color) B() float32 {
 func (c *Color) A() float32 {
 	return c[3]
 }
+
+// HSVToColor converts hue (degrees, wrapped into [0,360)), saturation and value ([0,1], clamped) to an opaque color
+func HSVToColor(h, s, v float32) color.NRGBA {
+	h = wrapHue(h)
+	s = Clamp(s, 0, 1)
+	v = Clamp(v, 0, 1)
+	chroma := v * s
+	return hueToColor(h, chroma, v-chroma)
+}
+
+// HSLToColor converts hue (degrees, wrapped into [0,360)), saturation and lightness ([0,1], clamped) to an
+// opaque color
+func HSLToColor(h, s, l float32) color.NRGBA {
+	h = wrapHue(h)
+	s = Clamp(s, 0, 1)
+	l = Clamp(l, 0, 1)
+	chroma := (1 - float32(math.Abs(float64(2*l-1)))) * s
+	return hueToColor(h, chroma, l-chroma/2)
+}
+
+// RGBToHSV converts a straight alpha color to hue (degrees in [0,360)), saturation and value ([0,1]). Alpha is ignored
+func RGBToHSV(c color.NRGBA) (h, s, v float32) {
+	r := float32(c.R) / 255
+	g := float32(c.G) / 255
+	b := float32(c.B) / 255
+	max := float32(math.Max(float64(r), math.Max(float64(g), float64(b))))
+	min := float32(math.Min(float64(r), math.Min(float64(g), float64(b))))
+	chroma := max - min
+
+	v = max
+	if max > 0 {
+		s = chroma / max
+	}
+	switch {
+	case chroma == 0:
+		h = 0
+	case max == r:
+		h = 60 * (g - b) / chroma
+	case max == g:
+		h = 60 * ((b-r)/chroma + 2)
+	default:
+		h = 60 * ((r-g)/chroma + 4)
+	}
+	return wrapHue(h), s, v
+}
+
+// wrapHue wraps a hue in degrees into [0,360)
+func wrapHue(h float32) float32 {
+	h = float32(math.Mod(float64(h), 360))
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// hueToColor builds a color from a hue in [0,360), its chroma and the amount to add to each channel
+func hueToColor(h, chroma, m float32) color.NRGBA {
+	x := chroma * (1 - float32(math.Abs(math.Mod(float64(h)/60, 2)-1)))
+	var r, g, b float32
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return color.NRGBA{
+		R: unitToByte(r + m),
+		G: unitToByte(g + m),
+		B: unitToByte(b + m),
+		A: 255,
+	}
+}
+
+// unitToByte converts a [0,1] component to [0,255], rounding and clamping
+func unitToByte(v float32) uint8 {
+	return uint8(Clamp(v, 0, 1)*255 + 0.5)
+}
+
+// ParseHexColor parses a color written as "#RGB", "#RRGGBB" or "#RRGGBBAA", the leading # being optional.
+// Hex colors are written with straight alpha, so "#FF000080" is a half transparent pure red. When the alpha is
+// missing the color is opaque
+func ParseHexColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	digits := make([]uint8, len(hex))
+	for i := 0; i < len(hex); i++ {
+		value, ok := hexDigit(hex[i])
+		if !ok {
+			return color.NRGBA{}, fmt.Errorf("invalid hex color %q: %q is not a hex digit", s, hex[i])
+		}
+		digits[i] = value
+	}
+
+	switch len(digits) {
+	case 3:
+		// Each digit is repeated: #F80 is #FF8800
+		return color.NRGBA{R: digits[0] * 17, G: digits[1] * 17, B: digits[2] * 17, A: 255}, nil
+	case 6:
+		return color.NRGBA{
+			R: digits[0]<<4 | digits[1],
+			G: digits[2]<<4 | digits[3],
+			B: digits[4]<<4 | digits[5],
+			A: 255,
+		}, nil
+	case 8:
+		return color.NRGBA{
+			R: digits[0]<<4 | digits[1],
+			G: digits[2]<<4 | digits[3],
+			B: digits[4]<<4 | digits[5],
+			A: digits[6]<<4 | digits[7],
+		}, nil
+	default:
+		return color.NRGBA{}, fmt.Errorf("invalid hex color %q: expected 3, 6 or 8 digits", s)
+	}
+}
+
+// hexDigit returns the value of a hexadecimal digit
+func hexDigit(c byte) (uint8, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// LerpColor interpolates every channel of two straight alpha colors, like the ones returned by ParseHexColor and
+// HSVToColor. t is clamped to [0,1], t=0 returns a and t=1 returns b
+func LerpColor(a, b color.NRGBA, t float32) color.NRGBA {
+	t = Clamp(t, 0, 1)
+	lerp := func(from, to uint8) uint8 {
+		return uint8(Lerp(float32(from), float32(to), t) + 0.5)
+	}
+	return color.NRGBA{
+		R: lerp(a.R, b.R),
+		G: lerp(a.G, b.G),
+		B: lerp(a.B, b.B),
+		A: lerp(a.A, b.A),
+	}
+}
+
+// Premultiply multiplies the color channels of a straight alpha color by alpha, giving the color.RGBA that
+// image.RGBA and premultiplied textures store
+func Premultiply(c color.NRGBA) color.RGBA {
+	return color.RGBA{
+		R: uint8((uint32(c.R)*uint32(c.A) + 127) / 255),
+		G: uint8((uint32(c.G)*uint32(c.A) + 127) / 255),
+		B: uint8((uint32(c.B)*uint32(c.A) + 127) / 255),
+		A: c.A,
+	}
+}
+
+// Unpremultiply divides the color channels by alpha, the inverse of Premultiply. Fully transparent colors have
+// no color left and return transparent black. The precision lost by Premultiply can't be recovered
+func Unpremultiply(c color.RGBA) color.NRGBA {
+	if c.A == 0 {
+		return color.NRGBA{}
+	}
+	unpremultiply := func(v uint8) uint8 {
+		return uint8(minInt((int(v)*255+int(c.A)/2)/int(c.A), 255))
+	}
+	return color.NRGBA{
+		R: unpremultiply(c.R),
+		G: unpremultiply(c.G),
+		B: unpremultiply(c.B),
+		A: c.A,
+	}
+}
@@ -0,0 +1,90 @@
+package gl_utils
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestLerpColor(t *testing.T) {
+	a := color.NRGBA{R: 10, G: 20, B: 30, A: 40}
+	b := color.NRGBA{R: 20, G: 40, B: 60, A: 80}
+	tests := []struct {
+		t        float32
+		expected color.NRGBA
+	}{
+		{0, a},
+		{1, b},
+		{0.5, color.NRGBA{R: 15, G: 30, B: 45, A: 60}},
+		{-1, a},
+		{2, b},
+	}
+	for _, test := range tests {
+		if got := LerpColor(a, b, test.t); got != test.expected {
+			t.Errorf("LerpColor(%v, %v, %v) = %v, expected %v", a, b, test.t, got, test.expected)
+		}
+	}
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	if got := LerpColor(color.NRGBA{}, white, 0.5); got != (color.NRGBA{R: 128, G: 128, B: 128, A: 128}) {
+		t.Errorf("midpoint between transparent black and white = %v, expected {128 128 128 128}", got)
+	}
+	// Straight alpha keeps the hue of a fade out: half way to transparent, red is still pure red
+	red := color.NRGBA{R: 255, A: 255}
+	if got := LerpColor(red, color.NRGBA{R: 255}, 0.5); got != (color.NRGBA{R: 255, A: 128}) {
+		t.Errorf("red fading out = %v, expected {255 0 0 128}", got)
+	}
+}
+
+func TestPremultiply(t *testing.T) {
+	straight := color.NRGBA{R: 255, G: 128, B: 0, A: 128}
+	premultiplied := Premultiply(straight)
+	if expected := (color.RGBA{R: 128, G: 64, B: 0, A: 128}); premultiplied != expected {
+		t.Errorf("Premultiply(%v) = %v, expected %v", straight, premultiplied, expected)
+	}
+	// The same value image/color computes
+	if expected := color.RGBAModel.Convert(straight).(color.RGBA); premultiplied != expected {
+		t.Errorf("Premultiply(%v) = %v, image/color gives %v", straight, premultiplied, expected)
+	}
+	if back := Unpremultiply(premultiplied); back != straight {
+		t.Errorf("Unpremultiply(%v) = %v, expected %v", premultiplied, back, straight)
+	}
+	if got := Unpremultiply(color.RGBA{R: 10, A: 0}); got != (color.NRGBA{}) {
+		t.Errorf("Unpremultiply of a transparent color = %v, expected transparent black", got)
+	}
+}
+
+func TestHSVRoundTrip(t *testing.T) {
+	for _, hue := range []float32{0, 30, 120, 210, 300} {
+		c := HSVToColor(hue, 1, 1)
+		if c.A != 255 {
+			t.Errorf("HSVToColor(%v, 1, 1) = %v, expected an opaque color", hue, c)
+		}
+		if h, s, v := RGBToHSV(c); float32(math.Abs(float64(h-hue))) > 1 || s != 1 || v != 1 {
+			t.Errorf("RGBToHSV(%v) = %v, %v, %v, expected %v, 1, 1", c, h, s, v, hue)
+		}
+	}
+	if got := HSLToColor(0, 1, 0.5); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("HSLToColor(0, 1, 0.5) = %v, expected opaque red", got)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected color.NRGBA
+	}{
+		{"#F80", color.NRGBA{R: 255, G: 136, A: 255}},
+		{"ff8800", color.NRGBA{R: 255, G: 136, A: 255}},
+		{"#FF000080", color.NRGBA{R: 255, A: 128}},
+	}
+	for _, test := range tests {
+		if got, err := ParseHexColor(test.s); err != nil || got != test.expected {
+			t.Errorf("ParseHexColor(%q) = %v, %v, expected %v", test.s, got, err, test.expected)
+		}
+	}
+	for _, s := range []string{"", "#12", "#12345", "#GG0000"} {
+		if _, err := ParseHexColor(s); err == nil {
+			t.Errorf("ParseHexColor(%q): expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package gl_utils
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// BezierQuadratic evaluates the quadratic Bezier curve with control points p0, p1, p2 at t in [0,1]
+func BezierQuadratic(p0, p1, p2 mgl32.Vec2, t float32) mgl32.Vec2 {
+	u := 1 - t
+	return p0.Mul(u * u).Add(p1.Mul(2 * u * t)).Add(p2.Mul(t * t))
+}
+
+// BezierCubic evaluates the cubic Bezier curve with control points p0, p1, p2, p3 at t in [0,1]
+func BezierCubic(p0, p1, p2, p3 mgl32.Vec2, t float32) mgl32.Vec2 {
+	u := 1 - t
+	return p0.Mul(u * u * u).
+		Add(p1.Mul(3 * u * u * t)).
+		Add(p2.Mul(3 * u * t * t)).
+		Add(p3.Mul(t * t * t))
+}
+
+// TessellateCubicBezier approximates a cubic Bezier curve with a polyline of the given number of segments.
+// It returns segments+1 points, from p0 to p3, that can be drawn as a line strip
+func TessellateCubicBezier(p0, p1, p2, p3 mgl32.Vec2, segments int) []mgl32.Vec2 {
+	if segments < 1 {
+		segments = 1
+	}
+	points := make([]mgl32.Vec2, 0, segments+1)
+	for index := 0; index <= segments; index++ {
+		points = append(points, BezierCubic(p0, p1, p2, p3, float32(index)/float32(segments)))
+	}
+	return points
+}
+
+// CatmullRom builds a polyline passing through all the control points using a uniform Catmull-Rom spline, with
+// segmentsPerSpan segments between two consecutive control points. The first and last points are duplicated to
+// give the curve its end tangents. Fewer than 2 control points return an empty slice
+func CatmullRom(points []mgl32.Vec2, segmentsPerSpan int) []mgl32.Vec2 {
+	if len(points) < 2 {
+		return nil
+	}
+	if segmentsPerSpan < 1 {
+		segmentsPerSpan = 1
+	}
+	spans := len(points) - 1
+	curve := make([]mgl32.Vec2, 0, spans*segmentsPerSpan+1)
+	for span := 0; span < spans; span++ {
+		p0 := points[maxInt(span-1, 0)]
+		p1 := points[span]
+		p2 := points[span+1]
+		p3 := points[minInt(span+2, len(points)-1)]
+		for index := 0; index < segmentsPerSpan; index++ {
+			curve = append(curve, catmullRomPoint(p0, p1, p2, p3, float32(index)/float32(segmentsPerSpan)))
+		}
+	}
+	return append(curve, points[len(points)-1])
+}
+
+// catmullRomPoint evaluates the span between p1 and p2 of a uniform Catmull-Rom spline at t in [0,1]
+func catmullRomPoint(p0, p1, p2, p3 mgl32.Vec2, t float32) mgl32.Vec2 {
+	t2 := t * t
+	t3 := t2 * t
+	return p1.Mul(2).
+		Add(p2.Sub(p0).Mul(t)).
+		Add(p0.Mul(2).Sub(p1.Mul(5)).Add(p2.Mul(4)).Sub(p3).Mul(t2)).
+		Add(p1.Mul(3).Sub(p0).Sub(p2.Mul(3)).Add(p3).Mul(t3)).
+		Mul(0.5)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestCatmullRomClosedCurveHasNoGap(t *testing.T) {
+	const segments = 1000
+	circle, err := CircleToPolygon(mgl32.Vec2{}, 50, segments, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A closed curve repeats the first control point at the end
+	curve := CatmullRom(append(circle, circle[0]), 4)
+	if len(curve) != segments*4+1 {
+		t.Fatalf("got %d points, expected %d", len(curve), segments*4+1)
+	}
+	if gap := curve[len(curve)-1].Sub(curve[0]).Len(); gap != 0 {
+		t.Errorf("the curve ends %f away from its start", gap)
+	}
+	// The spline passes through every control point
+	for i, point := range circle {
+		if !curve[i*4].ApproxEqualThreshold(point, 1e-4) {
+			t.Fatalf("point %d: got %v, expected %v", i, curve[i*4], point)
+		}
+	}
+}
+
+func TestCatmullRomTooFewPoints(t *testing.T) {
+	if curve := CatmullRom([]mgl32.Vec2{{1, 2}}, 8); curve != nil {
+		t.Errorf("got %v, expected nil", curve)
+	}
+}
@@ -0,0 +1,121 @@
+package gl_utils
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	debugDrawVertexShader = `
+        #version 410 core
+
+        uniform mat4 view_projection;
+
+        layout(location=0) in vec3 vertex;
+        layout(location=1) in vec4 color;
+
+        out vec4 color_out;
+
+        void main() {
+            gl_Position = view_projection * vec4(vertex, 1);
+            color_out = color;
+        }
+        ` + "\x00"
+
+	debugDrawFragmentShader = `
+        #version 410 core
+
+        in vec4 color_out;
+        out vec4 color;
+
+        void main() {
+            color = color_out;
+        }
+        ` + "\x00"
+)
+
+// debugDrawFloatsPerVertex x,y,z followed by r,g,b,a
+const debugDrawFloatsPerVertex = 7
+
+// debugDrawCircleSegments number of segments of the circles drawn by DebugDraw
+const debugDrawCircleSegments = 32
+
+// DebugDraw collects colored line segments during a frame and draws them all at once with its own shader, to
+// visualize bounding boxes, normals, paths and so on without any setup
+type DebugDraw struct {
+	shader   *ShaderProgram
+	mesh     *Mesh
+	vertices []float32
+}
+
+// NewDebugDraw compiles the shader and allocates the vertex buffer of a DebugDraw
+func NewDebugDraw() (*DebugDraw, error) {
+	shader, err := NewShaderProgramFromSource(debugDrawVertexShader, debugDrawFragmentShader)
+	if err != nil {
+		return nil, fmt.Errorf("creating debug draw: %w", err)
+	}
+	mesh := NewMeshWithUsage(nil, nil, []VertexAttribute{
+		{Location: 0, Size: 3, Offset: 0, Stride: debugDrawFloatsPerVertex},
+		{Location: 1, Size: 4, Offset: 3, Stride: debugDrawFloatsPerVertex},
+	}, gl.STREAM_DRAW)
+	return &DebugDraw{shader: shader, mesh: mesh}, nil
+}
+
+// Line queues a segment from a to b
+func (d *DebugDraw) Line(a, b mgl32.Vec3, color mgl32.Vec4) {
+	d.vertices = append(d.vertices,
+		a.X(), a.Y(), a.Z(), color.X(), color.Y(), color.Z(), color.W(),
+		b.X(), b.Y(), b.Z(), color.X(), color.Y(), color.Z(), color.W(),
+	)
+}
+
+// AABB queues the 12 edges of an axis aligned box
+func (d *DebugDraw) AABB(min, max mgl32.Vec3, color mgl32.Vec4) {
+	corners := [8]mgl32.Vec3{
+		{min.X(), min.Y(), min.Z()}, {max.X(), min.Y(), min.Z()},
+		{max.X(), max.Y(), min.Z()}, {min.X(), max.Y(), min.Z()},
+		{min.X(), min.Y(), max.Z()}, {max.X(), min.Y(), max.Z()},
+		{max.X(), max.Y(), max.Z()}, {min.X(), max.Y(), max.Z()},
+	}
+	for i := 0; i < 4; i++ {
+		d.Line(corners[i], corners[(i+1)%4], color)
+		d.Line(corners[i+4], corners[(i+1)%4+4], color)
+		d.Line(corners[i], corners[i+4], color)
+	}
+}
+
+// Circle queues a circle lying on the plane perpendicular to normal. Invalid circles (radius <= 0 or zero normal)
+// are ignored
+func (d *DebugDraw) Circle(center mgl32.Vec3, radius float32, normal mgl32.Vec3, color mgl32.Vec4) {
+	points, err := CircleToPolygon3D(center, radius, normal, debugDrawCircleSegments)
+	if err != nil {
+		return
+	}
+	for i := range points {
+		d.Line(points[i], points[(i+1)%len(points)], color)
+	}
+}
+
+// Flush draws the segments queued since the last Flush, transformed by viewProj (e.g. Camera3D.ViewProjection()),
+// and clears the queue. The depth test and blending state are the caller's
+func (d *DebugDraw) Flush(viewProj mgl32.Mat4) {
+	if len(d.vertices) == 0 {
+		return
+	}
+	d.mesh.UpdateVertices(d.vertices)
+	d.shader.Use()
+	d.shader.SetUniformMat4("view_projection", viewProj)
+	d.mesh.Draw(gl.LINES)
+	d.vertices = d.vertices[:0]
+}
+
+// Delete releases the shader and the vertex buffer. It's safe to call it more than once and on a nil DebugDraw
+func (d *DebugDraw) Delete() {
+	if d == nil {
+		return
+	}
+	d.shader.Delete()
+	d.mesh.Delete()
+}
@@ -0,0 +1,7 @@
+//go:build gl_debug
+// +build gl_debug
+
+package gl_utils
+
+// debugGL makes the texture and framebuffer operations check for OpenGL errors, build with -tags gl_debug
+const debugGL = true
@@ -0,0 +1,116 @@
+package gl_utils
+
+import "math"
+
+// EasingFunc maps a normalized time t in [0,1] to the animation progress, 0 at t=0 and 1 at t=1.
+// All the easing functions of the package clamp t to [0,1], so overshooting the animation time is harmless
+type EasingFunc func(t float32) float32
+
+// EaseLinear constant speed, no easing
+func EaseLinear(t float32) float32 {
+	return Clamp(t, 0, 1)
+}
+
+// Tween interpolates from a to b following an easing function at the normalized time t, e.g.
+// Tween(x0, x1, elapsed/duration, EaseOutBounce). A nil easing is EaseLinear
+func Tween(a, b, t float32, easing EasingFunc) float32 {
+	if easing == nil {
+		easing = EaseLinear
+	}
+	return Lerp(a, b, easing(t))
+}
+
+// EaseInQuad starts slow and accelerates
+func EaseInQuad(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return t * t
+}
+
+// EaseOutQuad starts fast and decelerates
+func EaseOutQuad(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates until halfway, then decelerates
+func EaseInOutQuad(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic starts slow and accelerates, more sharply than EaseInQuad
+func EaseInCubic(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return t * t * t
+}
+
+// EaseOutCubic starts fast and decelerates, more sharply than EaseOutQuad
+func EaseOutCubic(t float32) float32 {
+	t = Clamp(t, 0, 1) - 1
+	return t*t*t + 1
+}
+
+// EaseInOutCubic accelerates until halfway, then decelerates
+func EaseInOutCubic(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// EaseInSine starts slow following a quarter of sine wave
+func EaseInSine(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+// EaseOutSine decelerates following a quarter of sine wave
+func EaseOutSine(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+// EaseInOutSine accelerates and decelerates following half a sine wave
+func EaseInOutSine(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	return float32(-(math.Cos(math.Pi*float64(t)) - 1) / 2)
+}
+
+// EaseOutBounce reaches the end bouncing on it, like a dropped ball
+func EaseOutBounce(t float32) float32 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	t = Clamp(t, 0, 1)
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// EaseInBounce leaves the start bouncing on it, the mirror of EaseOutBounce
+func EaseInBounce(t float32) float32 {
+	return 1 - EaseOutBounce(1-Clamp(t, 0, 1))
+}
+
+// EaseInOutBounce bounces away from the start and then onto the end
+func EaseInOutBounce(t float32) float32 {
+	t = Clamp(t, 0, 1)
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
@@ -0,0 +1,27 @@
+package gl_utils
+
+import "testing"
+
+func TestEasingEndpoints(t *testing.T) {
+	easings := []EasingFunc{
+		EaseLinear, EaseInQuad, EaseOutQuad, EaseInOutQuad, EaseInCubic, EaseOutCubic, EaseInOutCubic,
+		EaseInSine, EaseOutSine, EaseInOutSine, EaseInBounce, EaseOutBounce, EaseInOutBounce,
+	}
+	for i, easing := range easings {
+		// Out of range times are clamped
+		for _, test := range []struct{ t, expected float32 }{{-1, 0}, {0, 0}, {1, 1}, {2, 1}} {
+			if got := easing(test.t); got < test.expected-1e-6 || got > test.expected+1e-6 {
+				t.Errorf("easing %d at %v = %v, expected %v", i, test.t, got, test.expected)
+			}
+		}
+	}
+}
+
+func TestTween(t *testing.T) {
+	if got := Tween(10, 20, 0.5, EaseInQuad); got != 12.5 {
+		t.Errorf("Tween(10, 20, 0.5, EaseInQuad) = %v, expected 12.5", got)
+	}
+	if got := Tween(10, 20, 0.25, nil); got != 12.5 {
+		t.Errorf("Tween(10, 20, 0.25, nil) = %v, expected a linear 12.5", got)
+	}
+}
@@ -0,0 +1,46 @@
+package gl_utils
+
+import "github.com/go-gl/gl/v4.1-core/gl"
+
+var extensions map[string]bool
+
+// HasExtension reports whether the current GL context supports an extension (e.g. "GL_KHR_debug").
+// The list of extensions is queried the first time and cached
+func HasExtension(name string) bool {
+	if extensions == nil {
+		var count int32
+		gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+		extensions = make(map[string]bool, count)
+		for i := uint32(0); i < uint32(count); i++ {
+			extensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i))] = true
+		}
+	}
+	return extensions[name]
+}
+
+var glMajorVersion, glMinorVersion int32
+
+// glVersionAtLeast reports whether the version of the current GL context is major.minor or newer.
+// The version is queried the first time and cached
+func glVersionAtLeast(major, minor int32) bool {
+	if glMajorVersion == 0 {
+		gl.GetIntegerv(gl.MAJOR_VERSION, &glMajorVersion)
+		gl.GetIntegerv(gl.MINOR_VERSION, &glMinorVersion)
+	}
+	return glMajorVersion > major || (glMajorVersion == major && glMinorVersion >= minor)
+}
+
+var debugLabels, debugLabelsChecked bool
+
+// objectLabel names an OpenGL object for the debuggers (RenderDoc, Nsight...) through KHR_debug, core since GL 4.3.
+// It does nothing when the context doesn't support it
+func objectLabel(identifier, id uint32, label string) {
+	if !debugLabelsChecked {
+		debugLabels = glVersionAtLeast(4, 3) || HasExtension("GL_KHR_debug")
+		debugLabelsChecked = true
+	}
+	if !debugLabels || id == 0 {
+		return
+	}
+	gl.ObjectLabel(identifier, id, -1, gl.Str(label+"\x00"))
+}
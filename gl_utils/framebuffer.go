@@ -0,0 +1,314 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// DepthAttachment kind of depth buffer attached to a framebuffer
+type DepthAttachment int
+
+// Depth attachments supported
+const (
+	// DepthAttachmentNone no depth buffer, depth testing doesn't work
+	DepthAttachmentNone DepthAttachment = iota
+	// DepthAttachmentRenderbuffer a 24-bit renderbuffer, fast but the depth can't be sampled
+	DepthAttachmentRenderbuffer
+	// DepthAttachmentTexture a 24-bit depth texture, available through DepthTexture() for shadows and SSAO
+	DepthAttachmentTexture
+)
+
+// Framebuffer an OpenGL framebuffer object rendering into textures
+type Framebuffer struct {
+	id                uint32
+	width             int32
+	height            int32
+	colorTextures     []*Texture
+	depthTexture      *Texture
+	depthRenderbuffer uint32
+	// Multisampled framebuffers render into a renderbuffer instead of a texture
+	colorRenderbuffer uint32
+	samples           int32
+}
+
+// NewFramebuffer creates a framebuffer with a RGBA color texture and the requested depth buffer attached
+func NewFramebuffer(width, height int32, depth DepthAttachment) (*Framebuffer, error) {
+	return NewFramebufferMRT(width, height, []int32{gl.RGBA8}, depth)
+}
+
+// NewFramebufferMRT creates a framebuffer with one color texture per internal format passed (e.g. a G-buffer with
+// albedo, normal and position), attached at gl.COLOR_ATTACHMENT0 onwards, and the requested depth buffer.
+// The formats are the sized color renderable ones with 1 to 4 components: normalized (gl.RGBA8, gl.SRGB8_ALPHA8,
+// gl.RGB10_A2, gl.R16...), floating point (gl.RGBA16F, gl.R32F, gl.R11F_G11F_B10F...) and integer (gl.RGBA32UI,
+// gl.R32I...), plus the unsized gl.RGBA and gl.RGB. Integer attachments need an integer output in the shader, e.g.
+// an object ID for picking
+func NewFramebufferMRT(width, height int32, formats []int32, depth DepthAttachment) (*Framebuffer, error) {
+	var maxAttachments int32
+	gl.GetIntegerv(gl.MAX_COLOR_ATTACHMENTS, &maxAttachments)
+	if len(formats) == 0 || len(formats) > int(maxAttachments) {
+		return nil, fmt.Errorf(
+			"creating framebuffer: %d color attachments requested, between 1 and %d supported",
+			len(formats), maxAttachments,
+		)
+	}
+
+	f := &Framebuffer{
+		width:  width,
+		height: height,
+	}
+	gl.GenFramebuffers(1, &f.id)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.id)
+
+	err := f.attachColorTextures(formats)
+	if err == nil {
+		err = f.attachDepth(depth)
+	}
+	if err == nil {
+		err = checkFramebufferStatus()
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if err != nil {
+		f.Delete()
+		return nil, fmt.Errorf("creating framebuffer: %w", err)
+	}
+	return f, nil
+}
+
+// NewFramebufferMS creates a multisampled framebuffer with a RGBA color renderbuffer and a 24-bit depth renderbuffer.
+// The number of samples is clamped between 1 and GL_MAX_SAMPLES. Multisampled buffers can't be sampled by the
+// shaders, ResolveTo copies the rendering into a regular framebuffer
+func NewFramebufferMS(width, height, samples int32) (*Framebuffer, error) {
+	var maxSamples int32
+	gl.GetIntegerv(gl.MAX_SAMPLES, &maxSamples)
+	if samples > maxSamples {
+		samples = maxSamples
+	}
+	if samples < 1 {
+		samples = 1
+	}
+
+	f := &Framebuffer{
+		width:   width,
+		height:  height,
+		samples: samples,
+	}
+	gl.GenFramebuffers(1, &f.id)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.id)
+
+	gl.GenRenderbuffers(1, &f.colorRenderbuffer)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, f.colorRenderbuffer)
+	gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, gl.RGBA8, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, f.colorRenderbuffer)
+
+	gl.GenRenderbuffers(1, &f.depthRenderbuffer)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, f.depthRenderbuffer)
+	gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, gl.DEPTH_COMPONENT24, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, f.depthRenderbuffer)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	err := checkFramebufferStatus()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if err != nil {
+		f.Delete()
+		return nil, fmt.Errorf("creating multisampled framebuffer: %w", err)
+	}
+	return f, nil
+}
+
+// ResolveTo averages the samples of a multisampled framebuffer into dst, which must have the same size since OpenGL
+// can't scale while resolving. The depth is copied too when dst has a depth buffer. The default framebuffer is bound
+// afterwards
+func (f *Framebuffer) ResolveTo(dst *Framebuffer) error {
+	if dst == nil {
+		return errors.New("resolving framebuffer: no destination")
+	}
+	if dst.width != f.width || dst.height != f.height {
+		return fmt.Errorf(
+			"resolving framebuffer: the destination is %dx%d, expected %dx%d", dst.width, dst.height, f.width, f.height,
+		)
+	}
+	mask := uint32(gl.COLOR_BUFFER_BIT)
+	if dst.depthTexture != nil || dst.depthRenderbuffer != 0 {
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, f.id)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, dst.id)
+	gl.BlitFramebuffer(0, 0, f.width, f.height, 0, 0, f.width, f.height, mask, gl.NEAREST)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return debugCheckGLError("resolving framebuffer")
+}
+
+// attachColorTextures creates and attaches the color textures to the currently bound framebuffer
+func (f *Framebuffer) attachColorTextures(formats []int32) error {
+	drawBuffers := make([]uint32, len(formats))
+	for i, internalFormat := range formats {
+		format, pixelType, err := colorAttachmentFormat(internalFormat)
+		if err != nil {
+			return err
+		}
+		colorTexture, err := NewEmptyTexture(int(f.width), int(f.height), internalFormat, format, pixelType)
+		if err != nil {
+			return err
+		}
+		f.colorTextures = append(f.colorTextures, colorTexture)
+		drawBuffers[i] = gl.COLOR_ATTACHMENT0 + uint32(i)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, drawBuffers[i], gl.TEXTURE_2D, colorTexture.id, 0)
+	}
+	gl.DrawBuffers(int32(len(drawBuffers)), &drawBuffers[0])
+	return nil
+}
+
+// colorAttachmentFormat returns a client format and type compatible with a color renderable internal format. No data
+// is uploaded, but OpenGL rejects the combinations that don't match, e.g. gl.RGBA with an integer format
+func colorAttachmentFormat(internalFormat int32) (format uint32, pixelType uint32, err error) {
+	switch internalFormat {
+	case gl.RGBA, gl.RGBA8, gl.SRGB8_ALPHA8, gl.RGB10_A2, gl.RGBA16, gl.RGBA16F, gl.RGBA32F:
+		format = gl.RGBA
+	case gl.RGB, gl.RGB8, gl.R11F_G11F_B10F, gl.RGB16F, gl.RGB32F:
+		format = gl.RGB
+	case gl.RG8, gl.RG16, gl.RG16F, gl.RG32F:
+		format = gl.RG
+	case gl.R8, gl.R16, gl.R16F, gl.R32F:
+		format = gl.RED
+	case gl.RGBA8UI, gl.RGBA16UI, gl.RGBA32UI, gl.RGB10_A2UI:
+		return gl.RGBA_INTEGER, gl.UNSIGNED_INT, nil
+	case gl.RGBA8I, gl.RGBA16I, gl.RGBA32I:
+		return gl.RGBA_INTEGER, gl.INT, nil
+	case gl.RG8UI, gl.RG16UI, gl.RG32UI:
+		return gl.RG_INTEGER, gl.UNSIGNED_INT, nil
+	case gl.RG8I, gl.RG16I, gl.RG32I:
+		return gl.RG_INTEGER, gl.INT, nil
+	case gl.R8UI, gl.R16UI, gl.R32UI:
+		return gl.RED_INTEGER, gl.UNSIGNED_INT, nil
+	case gl.R8I, gl.R16I, gl.R32I:
+		return gl.RED_INTEGER, gl.INT, nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported color attachment format 0x%X", internalFormat)
+	}
+	// Without data any type goes, this one matches the components of the storage like clientFormat does, so that the
+	// later uploads of SubImage find the right layout
+	switch internalFormat {
+	case gl.RGBA16, gl.RG16, gl.R16:
+		return format, gl.UNSIGNED_SHORT, nil
+	case gl.RGBA16F, gl.RGBA32F, gl.R11F_G11F_B10F, gl.RGB16F, gl.RGB32F, gl.RG16F, gl.RG32F, gl.R16F, gl.R32F:
+		return format, gl.FLOAT, nil
+	}
+	return format, gl.UNSIGNED_BYTE, nil
+}
+
+// attachDepth attaches the depth buffer to the currently bound framebuffer
+func (f *Framebuffer) attachDepth(depth DepthAttachment) error {
+	switch depth {
+	case DepthAttachmentNone:
+	case DepthAttachmentRenderbuffer:
+		gl.GenRenderbuffers(1, &f.depthRenderbuffer)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, f.depthRenderbuffer)
+		gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, f.width, f.height)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, f.depthRenderbuffer)
+	case DepthAttachmentTexture:
+		depthTexture, err := NewDepthTexture(f.width, f.height)
+		if err != nil {
+			return err
+		}
+		// Sampling the raw depth is the common case when it's not a shadow map
+		depthTexture.SetCompareMode(false)
+		f.depthTexture = depthTexture
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, depthTexture.id, 0)
+	default:
+		return fmt.Errorf("unknown depth attachment %d", depth)
+	}
+	return nil
+}
+
+// checkFramebufferStatus returns an error if the currently bound framebuffer is not complete
+func checkFramebufferStatus() error {
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("framebuffer incomplete, status 0x%X", status)
+	}
+	return debugCheckGLError("attaching buffers")
+}
+
+// Bind makes the framebuffer the target of the drawing operations. Setting the viewport to its size is up to the caller
+func (f *Framebuffer) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.id)
+}
+
+// Unbind restores the default framebuffer. Restoring the viewport is up to the caller
+func (f *Framebuffer) Unbind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// ColorTexture returns the texture attached as the first color attachment. Multisampled framebuffers have none
+func (f *Framebuffer) ColorTexture() *Texture {
+	return f.ColorTextureN(0)
+}
+
+// ColorTextureN returns the texture attached at gl.COLOR_ATTACHMENT0+i, or nil if there isn't one
+func (f *Framebuffer) ColorTextureN(i int) *Texture {
+	if i < 0 || i >= len(f.colorTextures) {
+		return nil
+	}
+	return f.colorTextures[i]
+}
+
+// NumColorTextures returns the number of color attachments
+func (f *Framebuffer) NumColorTextures() int {
+	return len(f.colorTextures)
+}
+
+// DepthTexture returns the depth texture, or nil if the framebuffer was not created with DepthAttachmentTexture
+func (f *Framebuffer) DepthTexture() *Texture {
+	return f.depthTexture
+}
+
+// Delete releases the framebuffer together with its attachments
+func (f *Framebuffer) Delete() {
+	if f == nil {
+		return
+	}
+	for _, texture := range f.colorTextures {
+		texture.Delete()
+	}
+	f.depthTexture.Delete()
+	if f.depthRenderbuffer != 0 {
+		gl.DeleteRenderbuffers(1, &f.depthRenderbuffer)
+		f.depthRenderbuffer = 0
+	}
+	if f.colorRenderbuffer != 0 {
+		gl.DeleteRenderbuffers(1, &f.colorRenderbuffer)
+		f.colorRenderbuffer = 0
+	}
+	if f.id != 0 {
+		gl.DeleteFramebuffers(1, &f.id)
+		f.id = 0
+	}
+}
+
+// SetLabel names the framebuffer in the GPU debuggers. It does nothing without KHR_debug
+func (f *Framebuffer) SetLabel(name string) {
+	objectLabel(gl.FRAMEBUFFER, f.id, name)
+}
+
+// ID returns the OpenGL ID of this framebuffer
+func (f *Framebuffer) ID() uint32 {
+	return f.id
+}
+
+// Width returns the framebuffer width in pixels
+func (f *Framebuffer) Width() int32 {
+	return f.width
+}
+
+// Height returns the framebuffer height in pixels
+func (f *Framebuffer) Height() int32 {
+	return f.height
+}
+
+// Samples returns the number of samples per pixel of a multisampled framebuffer, 0 for the other framebuffers
+func (f *Framebuffer) Samples() int32 {
+	return f.samples
+}
@@ -0,0 +1,146 @@
+//go:build gl_context
+// +build gl_context
+
+package gl_utils
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	vertexShaderDepth = `
+        #version 410 core
+
+        layout(location=0) in vec3 vertex;
+
+        void main() {
+            gl_Position = vec4(vertex, 1);
+        }
+        `
+
+	fragmentShaderDepth = `
+        #version 410 core
+
+        uniform vec4 color;
+        out vec4 out_color;
+
+        void main() {
+            out_color = color;
+        }
+        `
+)
+
+// drawTriangle draws a triangle covering the center of the viewport at a depth in normalized device coordinates
+func drawTriangle(shader *ShaderProgram, depth float32, c mgl32.Vec4) {
+	mesh := NewMesh([]float32{
+		-1, -1, depth,
+		3, -1, depth,
+		-1, 3, depth,
+	}, []VertexAttribute{{Location: 0, Size: 3, Offset: 0, Stride: 3}})
+	defer mesh.Delete()
+	shader.SetUniformVec4("color", c)
+	mesh.Draw(gl.TRIANGLES)
+}
+
+func TestDepthAttachmentOccludes(t *testing.T) {
+	withContext(t)
+	shader, err := NewShaderProgramFromSource(vertexShaderDepth, fragmentShaderDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shader.Delete()
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	tests := []struct {
+		depth    DepthAttachment
+		expected color.NRGBA
+	}{
+		{DepthAttachmentRenderbuffer, red},
+		{DepthAttachmentTexture, red},
+		// Without a depth buffer the last triangle drawn wins
+		{DepthAttachmentNone, blue},
+	}
+	for _, test := range tests {
+		framebuffer := renderTarget(t, 4, 4, test.depth)
+		gl.Enable(gl.DEPTH_TEST)
+		gl.DepthFunc(gl.LESS)
+		shader.Use()
+		drawTriangle(shader, -0.5, mgl32.Vec4{1, 0, 0, 1})
+		// Farther, drawn after the nearer one
+		drawTriangle(shader, 0.5, mgl32.Vec4{0, 0, 1, 1})
+		gl.Disable(gl.DEPTH_TEST)
+		framebuffer.Unbind()
+
+		if got := renderedPixel(t, framebuffer, 2, 2); got != test.expected {
+			t.Errorf("depth attachment %d: got %v, expected %v", test.depth, got, test.expected)
+		}
+		if test.depth == DepthAttachmentTexture && framebuffer.DepthTexture() == nil {
+			t.Error("no depth texture with DepthAttachmentTexture")
+		}
+		if test.depth != DepthAttachmentTexture && framebuffer.DepthTexture() != nil {
+			t.Errorf("depth attachment %d: unexpected depth texture", test.depth)
+		}
+		framebuffer.Delete()
+	}
+}
+
+func TestColorAttachmentFormats(t *testing.T) {
+	withContext(t)
+	formats := []int32{gl.RGBA8, gl.RGBA16F, gl.R32F, gl.RGBA32UI, gl.R32I, gl.RG16, gl.RGB10_A2}
+	for _, format := range formats {
+		framebuffer, err := NewFramebufferMRT(4, 4, []int32{format}, DepthAttachmentNone)
+		if err != nil {
+			t.Errorf("format 0x%X: %v", format, err)
+			continue
+		}
+		if code := gl.GetError(); code != gl.NO_ERROR {
+			t.Errorf("format 0x%X: GL error 0x%X", format, code)
+		}
+		framebuffer.Delete()
+	}
+	// A G-buffer with an object ID for picking
+	framebuffer, err := NewFramebufferMRT(4, 4, []int32{gl.RGBA8, gl.RGBA16F, gl.R32UI}, DepthAttachmentTexture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	framebuffer.Delete()
+	if _, err := NewFramebufferMRT(4, 4, []int32{gl.DEPTH_COMPONENT24}, DepthAttachmentNone); err == nil {
+		t.Error("a depth format as color attachment: expected an error")
+	}
+}
+
+func TestResolveTo(t *testing.T) {
+	withContext(t)
+	multisampled, err := NewFramebufferMS(4, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer multisampled.Delete()
+	multisampled.Bind()
+	gl.Viewport(0, 0, 4, 4)
+	gl.ClearColor(1, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.ClearColor(0, 0, 0, 1)
+
+	if err := multisampled.ResolveTo(nil); err == nil {
+		t.Error("nil destination: expected an error")
+	}
+	smaller := renderTarget(t, 2, 2, DepthAttachmentNone)
+	defer smaller.Delete()
+	if err := multisampled.ResolveTo(smaller); err == nil {
+		t.Error("2x2 destination: expected an error")
+	}
+	target := renderTarget(t, 4, 4, DepthAttachmentRenderbuffer)
+	defer target.Delete()
+	if err := multisampled.ResolveTo(target); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderedPixel(t, target, 3, 3); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("resolved pixel %v, expected opaque red", got)
+	}
+}
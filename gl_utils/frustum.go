@@ -0,0 +1,69 @@
+package gl_utils
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Frustum the volume seen by a camera, as six planes (left, right, bottom, top, near, far) with the normals pointing
+// inside. Each plane is a,b,c,d with a*x + b*y + c*z + d = 0 and a,b,c normalized, so d gives distances in world units
+type Frustum struct {
+	planes [6]mgl32.Vec4
+}
+
+// FrustumFromMatrix extracts the planes of the frustum from a view-projection matrix (e.g.
+// Camera3D.ViewProjection()) with the Gribb-Hartmann method. Using a projection matrix alone gives the frustum in
+// view space
+func FrustumFromMatrix(viewProj mgl32.Mat4) Frustum {
+	row0, row1, row2, row3 := viewProj.Row(0), viewProj.Row(1), viewProj.Row(2), viewProj.Row(3)
+	f := Frustum{planes: [6]mgl32.Vec4{
+		row3.Add(row0), // left
+		row3.Sub(row0), // right
+		row3.Add(row1), // bottom
+		row3.Sub(row1), // top
+		row3.Add(row2), // near
+		row3.Sub(row2), // far
+	}}
+	for i, plane := range f.planes {
+		length := plane.Vec3().Len()
+		if length > 0 {
+			f.planes[i] = plane.Mul(1 / length)
+		}
+	}
+	return f
+}
+
+// Frustum returns the frustum seen by the camera, in world space
+func (c *Camera3D) Frustum() Frustum {
+	return FrustumFromMatrix(c.ViewProjection())
+}
+
+// Planes returns the six planes of the frustum: left, right, bottom, top, near and far
+func (f Frustum) Planes() [6]mgl32.Vec4 {
+	return f.planes
+}
+
+// ContainsSphere tests if a sphere is at least partly inside the frustum
+func (f Frustum) ContainsSphere(center mgl32.Vec3, radius float32) bool {
+	for _, plane := range f.planes {
+		if plane.Vec3().Dot(center)+plane.W() < -radius {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB tests if an axis aligned box is at least partly inside the frustum. Like most frustum culling tests
+// it's conservative: big boxes near the corners of the frustum can be reported as intersecting while being outside
+func (f Frustum) IntersectsAABB(min, max mgl32.Vec3) bool {
+	for _, plane := range f.planes {
+		// The corner of the box furthest along the plane normal
+		corner := min
+		for axis := 0; axis < 3; axis++ {
+			if plane[axis] >= 0 {
+				corner[axis] = max[axis]
+			}
+		}
+		if plane.Vec3().Dot(corner)+plane.W() < 0 {
+			return false
+		}
+	}
+	return true
+}
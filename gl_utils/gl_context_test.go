@@ -0,0 +1,63 @@
+//go:build gl_context
+// +build gl_context
+
+package gl_utils
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/maxfish/gl_utils/gl_utils/internal/glcontext"
+)
+
+// The tests in the files built with the gl_context tag run OpenGL calls on a headless context:
+// go test -tags gl_context ./...
+func TestMain(m *testing.M) {
+	if err := glcontext.Create(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := gl.InitWithProcAddrFunc(glcontext.ProcAddress); err != nil {
+		fmt.Fprintln(os.Stderr, "initializing OpenGL:", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	glcontext.Destroy()
+	os.Exit(code)
+}
+
+// withContext makes the OpenGL context current for the rest of the test
+func withContext(t *testing.T) {
+	t.Helper()
+	if err := glcontext.MakeCurrent(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(glcontext.Release)
+}
+
+// renderTarget creates a framebuffer and makes it the target of the drawing, with a matching viewport
+func renderTarget(t *testing.T, width, height int32, depth DepthAttachment) *Framebuffer {
+	t.Helper()
+	framebuffer, err := NewFramebuffer(width, height, depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	framebuffer.Bind()
+	gl.Viewport(0, 0, width, height)
+	gl.ClearColor(0, 0, 0, 1)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	return framebuffer
+}
+
+// renderedPixel reads a pixel of the color texture of a framebuffer, with 0,0 at the bottom-left corner
+func renderedPixel(t *testing.T, framebuffer *Framebuffer, x, y int) color.NRGBA {
+	t.Helper()
+	img, err := framebuffer.ColorTexture().ToImage(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+}
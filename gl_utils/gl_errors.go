@@ -0,0 +1,57 @@
+package gl_utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// maxGLErrors bounds the errors drained by CheckGLError, glGetError may never return GL_NO_ERROR without a context
+const maxGLErrors = 32
+
+// CheckGLError drains the OpenGL error queue and returns an error naming all the errors found, prefixed by context
+// (e.g. "uploading texture"), or nil if there were none
+func CheckGLError(context string) error {
+	var names []string
+	for i := 0; i < maxGLErrors; i++ {
+		code := gl.GetError()
+		if code == gl.NO_ERROR {
+			break
+		}
+		names = append(names, glErrorName(code))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: OpenGL error %s", context, strings.Join(names, ", "))
+}
+
+// glErrorName returns the name of an OpenGL error code
+func glErrorName(code uint32) string {
+	switch code {
+	case gl.INVALID_ENUM:
+		return "GL_INVALID_ENUM"
+	case gl.INVALID_VALUE:
+		return "GL_INVALID_VALUE"
+	case gl.INVALID_OPERATION:
+		return "GL_INVALID_OPERATION"
+	case gl.INVALID_FRAMEBUFFER_OPERATION:
+		return "GL_INVALID_FRAMEBUFFER_OPERATION"
+	case gl.OUT_OF_MEMORY:
+		return "GL_OUT_OF_MEMORY"
+	case gl.STACK_UNDERFLOW:
+		return "GL_STACK_UNDERFLOW"
+	case gl.STACK_OVERFLOW:
+		return "GL_STACK_OVERFLOW"
+	}
+	return fmt.Sprintf("0x%X", code)
+}
+
+// debugCheckGLError calls CheckGLError when the package is built with the gl_debug tag, and returns nil otherwise
+func debugCheckGLError(context string) error {
+	if !debugGL {
+		return nil
+	}
+	return CheckGLError(context)
+}
@@ -0,0 +1,118 @@
+package gl_utils
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// srgbToLinearTable converts the 8-bit sRGB components to linear light
+var srgbToLinearTable [256]float32
+
+func init() {
+	for i := range srgbToLinearTable {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			srgbToLinearTable[i] = float32(c / 12.92)
+		} else {
+			srgbToLinearTable[i] = float32(math.Pow((c+0.055)/1.055, 2.4))
+		}
+	}
+}
+
+// linearToSRGB converts a linear light component in [0,1] back to sRGB
+func linearToSRGB(c float32) float32 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return float32(1.055*math.Pow(float64(c), 1/2.4) - 0.055)
+}
+
+// resizeWeight the contribution of a source pixel to a destination one
+type resizeWeight struct {
+	index  int
+	weight float32
+}
+
+// ResizeImage scales an image to width x height with a box filter: every destination pixel is the average of the
+// source pixels it covers, weighted by the covered area. The average is done in linear light with premultiplied
+// alpha, so downscaling doesn't darken the edges between bright and dark areas like averaging the sRGB values does.
+// The box filter is fast (two passes, each source pixel read once per pass) and ideal for shrinking images, while
+// enlarging them gives blocky results similar to nearest filtering. Non positive sizes return an empty image
+func ResizeImage(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, maxInt(width, 0), maxInt(height, 0)))
+	srcSize := src.Bounds().Size()
+	if width <= 0 || height <= 0 || srcSize.X == 0 || srcSize.Y == 0 {
+		return dst
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, srcSize.X, srcSize.Y))
+	draw.Draw(nrgba, nrgba.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	// Linear, alpha-premultiplied source
+	linear := make([]float32, len(nrgba.Pix))
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		alpha := float32(nrgba.Pix[i+3]) / 255
+		linear[i] = srgbToLinearTable[nrgba.Pix[i]] * alpha
+		linear[i+1] = srgbToLinearTable[nrgba.Pix[i+1]] * alpha
+		linear[i+2] = srgbToLinearTable[nrgba.Pix[i+2]] * alpha
+		linear[i+3] = alpha
+	}
+
+	// Horizontal pass into srcSize.Y rows of width pixels, then vertical pass into height rows
+	horizontal := make([]float32, width*srcSize.Y*4)
+	for x, weights := range boxWeights(srcSize.X, width) {
+		for y := 0; y < srcSize.Y; y++ {
+			out := (y*width + x) * 4
+			for _, w := range weights {
+				in := (y*srcSize.X + w.index) * 4
+				for c := 0; c < 4; c++ {
+					horizontal[out+c] += linear[in+c] * w.weight
+				}
+			}
+		}
+	}
+	resized := make([]float32, width*height*4)
+	for y, weights := range boxWeights(srcSize.Y, height) {
+		for x := 0; x < width; x++ {
+			out := (y*width + x) * 4
+			for _, w := range weights {
+				in := (w.index*width + x) * 4
+				for c := 0; c < 4; c++ {
+					resized[out+c] += horizontal[in+c] * w.weight
+				}
+			}
+		}
+	}
+
+	for i := 0; i < len(resized); i += 4 {
+		alpha := Clamp(resized[i+3], 0, 1)
+		if alpha == 0 {
+			continue
+		}
+		for c := 0; c < 3; c++ {
+			// image.RGBA stores the sRGB components premultiplied by alpha
+			srgb := linearToSRGB(Clamp(resized[i+c]/alpha, 0, 1))
+			dst.Pix[i+c] = uint8(srgb*alpha*255 + 0.5)
+		}
+		dst.Pix[i+3] = uint8(alpha*255 + 0.5)
+	}
+	return dst
+}
+
+// boxWeights returns, for every destination pixel along an axis, the source pixels it covers and their weights,
+// which add up to 1
+func boxWeights(srcSize, dstSize int) [][]resizeWeight {
+	scale := float64(srcSize) / float64(dstSize)
+	weights := make([][]resizeWeight, dstSize)
+	for i := range weights {
+		start := float64(i) * scale
+		end := start + scale
+		for index := int(start); index < srcSize && float64(index) < end; index++ {
+			covered := math.Min(end, float64(index+1)) - math.Max(start, float64(index))
+			if covered > 0 {
+				weights[i] = append(weights[i], resizeWeight{index: index, weight: float32(covered / scale)})
+			}
+		}
+	}
+	return weights
+}
@@ -0,0 +1,143 @@
+//go:build gl_context
+// +build gl_context
+
+// Package glcontext creates a headless OpenGL 4.1 core context with EGL, for the tests that need to run OpenGL
+// calls. It works with any EGL driver, including the Mesa software renderer on machines without a GPU or a display.
+// The tests using it are built only with the gl_context tag: go test -tags gl_context ./...
+package glcontext
+
+/*
+#cgo LDFLAGS: -lEGL
+#include <stdlib.h>
+#include <EGL/egl.h>
+#include <EGL/eglext.h>
+
+#ifndef EGL_PLATFORM_SURFACELESS_MESA
+#define EGL_PLATFORM_SURFACELESS_MESA 0x31DD
+#endif
+
+static EGLDisplay display = EGL_NO_DISPLAY;
+static EGLContext context = EGL_NO_CONTEXT;
+
+// The platform display doesn't need a window system, fall back to the default one when it's missing
+static EGLDisplay openDisplay() {
+	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+		(PFNEGLGETPLATFORMDISPLAYEXTPROC) eglGetProcAddress("eglGetPlatformDisplayEXT");
+	if (getPlatformDisplay != NULL) {
+		EGLDisplay surfaceless = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+		if (surfaceless != EGL_NO_DISPLAY) {
+			return surfaceless;
+		}
+	}
+	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+}
+
+static const char *createContext() {
+	display = openDisplay();
+	if (display == EGL_NO_DISPLAY) {
+		return "no EGL display";
+	}
+	if (!eglInitialize(display, NULL, NULL)) {
+		return "eglInitialize failed";
+	}
+	if (!eglBindAPI(EGL_OPENGL_API)) {
+		return "desktop OpenGL is not supported";
+	}
+	const EGLint configAttributes[] = {
+		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+		EGL_RED_SIZE, 8, EGL_GREEN_SIZE, 8, EGL_BLUE_SIZE, 8, EGL_ALPHA_SIZE, 8,
+		EGL_DEPTH_SIZE, 24,
+		EGL_NONE
+	};
+	EGLConfig config;
+	EGLint count = 0;
+	if (!eglChooseConfig(display, configAttributes, &config, 1, &count) || count == 0) {
+		config = NULL;
+	}
+	const EGLint contextAttributes[] = {
+		EGL_CONTEXT_MAJOR_VERSION, 4,
+		EGL_CONTEXT_MINOR_VERSION, 1,
+		EGL_CONTEXT_OPENGL_PROFILE_MASK, EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+		EGL_NONE
+	};
+	context = eglCreateContext(display, config, EGL_NO_CONTEXT, contextAttributes);
+	if (context == EGL_NO_CONTEXT) {
+		return "an OpenGL 4.1 core context can't be created";
+	}
+	return NULL;
+}
+
+// Without a surface the default framebuffer is incomplete, the tests render into framebuffer objects
+static const char *makeCurrent() {
+	if (!eglMakeCurrent(display, EGL_NO_SURFACE, EGL_NO_SURFACE, context)) {
+		return "eglMakeCurrent failed, EGL_KHR_surfaceless_context is needed";
+	}
+	return NULL;
+}
+
+static void release() {
+	eglMakeCurrent(display, EGL_NO_SURFACE, EGL_NO_SURFACE, EGL_NO_CONTEXT);
+}
+
+static void destroyContext() {
+	if (display != EGL_NO_DISPLAY) {
+		eglMakeCurrent(display, EGL_NO_SURFACE, EGL_NO_SURFACE, EGL_NO_CONTEXT);
+		if (context != EGL_NO_CONTEXT) {
+			eglDestroyContext(display, context);
+		}
+		eglTerminate(display);
+	}
+	display = EGL_NO_DISPLAY;
+	context = EGL_NO_CONTEXT;
+}
+
+static void *procAddress(const char *name) {
+	return (void *) eglGetProcAddress(name);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// Create makes the context, usually from TestMain, then each test using OpenGL calls MakeCurrent. Initialize the
+// bindings with gl.InitWithProcAddrFunc(ProcAddress)
+func Create() error {
+	if message := C.createContext(); message != nil {
+		C.destroyContext()
+		return errors.New("creating the OpenGL context: " + C.GoString(message))
+	}
+	return nil
+}
+
+// MakeCurrent locks the calling goroutine to its thread and makes the context current there. Every test runs in its
+// own goroutine, it must call Release when it's done so that the next test can take the context
+func MakeCurrent() error {
+	runtime.LockOSThread()
+	if message := C.makeCurrent(); message != nil {
+		runtime.UnlockOSThread()
+		return errors.New("making the OpenGL context current: " + C.GoString(message))
+	}
+	return nil
+}
+
+// Release detaches the context from the thread of the calling goroutine and unlocks it
+func Release() {
+	C.release()
+	runtime.UnlockOSThread()
+}
+
+// Destroy releases the context made by Create
+func Destroy() {
+	C.destroyContext()
+}
+
+// ProcAddress returns the address of an OpenGL function, for gl.InitWithProcAddrFunc
+func ProcAddress(name string) unsafe.Pointer {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return C.procAddress(cName)
+}
@@ -0,0 +1,141 @@
+package gl_utils
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SegmentIntersect returns the point where the segments a0-a1 and b0-b1 cross. Endpoints count as part of the
+// segments. Parallel segments, including the collinear ones that overlap, are reported as not intersecting since
+// they don't meet in a single point
+func SegmentIntersect(a0, a1, b0, b1 mgl32.Vec2) (point mgl32.Vec2, intersects bool) {
+	t, u, ok := lineCrossing(a0, a1, b0, b1)
+	if !ok || t < 0 || t > 1 || u < 0 || u > 1 {
+		return mgl32.Vec2{}, false
+	}
+	return a0.Add(a1.Sub(a0).Mul(t)), true
+}
+
+// lineIntersection returns the point where the infinite lines through a0-a1 and b0-b1 cross
+func lineIntersection(a0, a1, b0, b1 mgl32.Vec2) (mgl32.Vec2, bool) {
+	t, _, ok := lineCrossing(a0, a1, b0, b1)
+	if !ok {
+		return mgl32.Vec2{}, false
+	}
+	return a0.Add(a1.Sub(a0).Mul(t)), true
+}
+
+// lineCrossing returns where the lines through a0-a1 and b0-b1 cross, as the fractions t of a0-a1 and u of b0-b1.
+// The lines are parallel, and ok false, when the sine of their angle is below parallelEpsilon: the threshold scales
+// with the lengths of the segments, so it holds for the tiny and the huge ones alike. Zero length segments are
+// parallel to everything
+func lineCrossing(a0, a1, b0, b1 mgl32.Vec2) (t, u float32, ok bool) {
+	const parallelEpsilon = 1e-6
+	r := a1.Sub(a0)
+	s := b1.Sub(b0)
+	denominator := r.X()*s.Y() - r.Y()*s.X()
+	if float32(math.Abs(float64(denominator))) <= parallelEpsilon*r.Len()*s.Len() {
+		return 0, 0, false
+	}
+	ab := b0.Sub(a0)
+	t = (ab.X()*s.Y() - ab.Y()*s.X()) / denominator
+	u = (ab.X()*r.Y() - ab.Y()*r.X()) / denominator
+	return t, u, true
+}
+
+// ClosestPointOnSegment returns the point of the segment a-b nearest to p. A zero length segment returns a
+func ClosestPointOnSegment(p, a, b mgl32.Vec2) mgl32.Vec2 {
+	ab := b.Sub(a)
+	lengthSquared := ab.Dot(ab)
+	if lengthSquared == 0 {
+		return a
+	}
+	t := Clamp(p.Sub(a).Dot(ab)/lengthSquared, 0, 1)
+	return a.Add(ab.Mul(t))
+}
+
+// DistanceToSegment returns the distance between p and the nearest point of the segment a-b
+func DistanceToSegment(p, a, b mgl32.Vec2) float32 {
+	return p.Sub(ClosestPointOnSegment(p, a, b)).Len()
+}
+
+// AABBIntersect tests if two axis aligned boxes, given as min and max corners like GetBoundingBox returns them,
+// overlap. The test is inclusive: boxes touching on an edge or a corner intersect
+func AABBIntersect(minA, maxA, minB, maxB mgl32.Vec2) bool {
+	return minA.X() <= maxB.X() && maxA.X() >= minB.X() &&
+		minA.Y() <= maxB.Y() && maxA.Y() >= minB.Y()
+}
+
+// AABBContainsPoint tests if a point is inside an axis aligned box. Points on the edges are inside
+func AABBContainsPoint(min, max, p mgl32.Vec2) bool {
+	return p.X() >= min.X() && p.X() <= max.X() &&
+		p.Y() >= min.Y() && p.Y() <= max.Y()
+}
+
+// CircleIntersect tests if two circles overlap. Circles touching in a single point intersect
+func CircleIntersect(centerA mgl32.Vec2, rA float32, centerB mgl32.Vec2, rB float32) bool {
+	d := centerB.Sub(centerA)
+	radii := rA + rB
+	return d.Dot(d) <= radii*radii
+}
+
+// RayAABB tests a ray against an axis aligned box using the slab method. tMin is the distance along the ray, in
+// units of dir, of the nearest intersection, so hits can be sorted. A ray starting inside the box returns 0.
+// Boxes behind the origin are not hit. Direction components equal to 0 are handled without dividing by zero
+func RayAABB(origin, dir mgl32.Vec3, boxMin, boxMax mgl32.Vec3) (tMin float32, hit bool) {
+	tNear := float32(-math.MaxFloat32)
+	tFar := float32(math.MaxFloat32)
+	for axis := 0; axis < 3; axis++ {
+		if dir[axis] == 0 {
+			// Parallel to the slab: either always inside it or never
+			if origin[axis] < boxMin[axis] || origin[axis] > boxMax[axis] {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (boxMin[axis] - origin[axis]) / dir[axis]
+		t2 := (boxMax[axis] - origin[axis]) / dir[axis]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tNear {
+			tNear = t1
+		}
+		if t2 < tFar {
+			tFar = t2
+		}
+		if tNear > tFar {
+			return 0, false
+		}
+	}
+	if tFar < 0 {
+		return 0, false
+	}
+	if tNear < 0 {
+		tNear = 0
+	}
+	return tNear, true
+}
+
+// Barycentric returns the barycentric coordinates of p in the triangle a, b, c: p = u*a + v*b + w*c, u+v+w = 1.
+// Degenerate triangles with no area return -1 for all the coordinates, which lies outside of any triangle
+func Barycentric(p, a, b, c mgl32.Vec2) (u, v, w float32) {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ap := p.Sub(a)
+	denominator := ab.X()*ac.Y() - ac.X()*ab.Y()
+	if denominator == 0 {
+		return -1, -1, -1
+	}
+	v = (ap.X()*ac.Y() - ac.X()*ap.Y()) / denominator
+	w = (ab.X()*ap.Y() - ap.X()*ab.Y()) / denominator
+	return 1 - v - w, v, w
+}
+
+// PointInTriangle tests if p is inside the triangle a, b, c, of any winding. Points on the edges are inside,
+// degenerate triangles contain no point
+func PointInTriangle(p, a, b, c mgl32.Vec2) bool {
+	u, v, w := Barycentric(p, a, b, c)
+	return u >= 0 && v >= 0 && w >= 0
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"github.com/go-gl/mathgl/mgl64"
 	"math"
+	"math/rand"
+	"sort"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-// CircleToPolygon approximate a circle shape with a regular polygon
+// CircleToPolygon approximate a circle shape with a regular polygon. The vertices are in counter-clockwise order (with
+// Y pointing up), starting from startAngle
 func CircleToPolygon(center mgl32.Vec2, radius float32, numSegments int, startAngle float32) ([]mgl32.Vec2, error) {
 	if radius <= 0 {
 		return nil, errors.New("Radius cannot be <=0")
@@ -16,21 +19,367 @@ func CircleToPolygon(center mgl32.Vec2, radius float32, numSegments int, startAn
 	if numSegments < 3 {
 		return nil, errors.New("numSegments must be >= 3")
 	}
-	point := mgl32.Rotate2D(startAngle).Mul2x1(mgl32.Vec2{radius, 0})
-	vertices := make([]mgl32.Vec2, 0, numSegments*2)
-	rotation := mgl32.Rotate2D(float32((math.Pi * 2.0) / float64(numSegments)))
+	vertices := make([]mgl32.Vec2, 0, numSegments)
+	step := (math.Pi * 2.0) / float64(numSegments)
 
+	// Every angle is computed from the start one, rotating the previous point would accumulate the rounding errors
 	for index := 0; index < numSegments; index++ {
-		p := point.Add(center)
+		angle := float64(startAngle) + float64(index)*step
+		p := mgl32.Vec2{radius * float32(math.Cos(angle)), radius * float32(math.Sin(angle))}.Add(center)
 		vertices = append(vertices, p)
-		point = rotation.Mul2x1(point)
 	}
 
 	return vertices, nil
 }
 
+// CircleToPolygon3D approximate a circle lying on the plane perpendicular to normal with a regular polygon. The
+// vertices are in counter-clockwise order seen from the side the normal points to
+func CircleToPolygon3D(center mgl32.Vec3, radius float32, normal mgl32.Vec3, numSegments int) ([]mgl32.Vec3, error) {
+	if radius <= 0 {
+		return nil, errors.New("Radius cannot be <=0")
+	}
+	if numSegments < 3 {
+		return nil, errors.New("numSegments must be >= 3")
+	}
+	if normal.Len() == 0 {
+		return nil, errors.New("normal cannot be a zero vector")
+	}
+	normal = normal.Normalize()
+	// Any axis not parallel to the normal gives a basis, the least aligned one is the most precise
+	helper := mgl32.Vec3{1, 0, 0}
+	if math.Abs(float64(normal.X())) > 0.9 {
+		helper = mgl32.Vec3{0, 1, 0}
+	}
+	u := helper.Cross(normal).Normalize()
+	v := normal.Cross(u)
+
+	vertices := make([]mgl32.Vec3, 0, numSegments)
+	step := (math.Pi * 2.0) / float64(numSegments)
+	for index := 0; index < numSegments; index++ {
+		angle := float64(index) * step
+		offset := u.Mul(radius * float32(math.Cos(angle))).Add(v.Mul(radius * float32(math.Sin(angle))))
+		vertices = append(vertices, center.Add(offset))
+	}
+	return vertices, nil
+}
+
+// CircleToFilledPolygon approximate a filled circle: the center followed by the points of CircleToPolygon, and the
+// indices to draw them as a triangle fan (e.g. an indexed Mesh drawn with gl.TRIANGLE_FAN). The fan ends on the
+// first point of the ring to close it
+func CircleToFilledPolygon(center mgl32.Vec2, radius float32, numSegments int, startAngle float32) ([]mgl32.Vec2, []uint32, error) {
+	ring, err := CircleToPolygon(center, radius, numSegments, startAngle)
+	if err != nil {
+		return nil, nil, err
+	}
+	vertices := append([]mgl32.Vec2{center}, ring...)
+	indices := make([]uint32, 0, len(vertices)+1)
+	for index := range vertices {
+		indices = append(indices, uint32(index))
+	}
+	indices = append(indices, 1)
+	return vertices, indices, nil
+}
+
+// EllipseToPolygon approximate an axis aligned ellipse shape with a polygon
+func EllipseToPolygon(center mgl32.Vec2, radiusX, radiusY float32, numSegments int, startAngle float32) ([]mgl32.Vec2, error) {
+	if radiusX <= 0 || radiusY <= 0 {
+		return nil, errors.New("Radius cannot be <=0")
+	}
+	if numSegments < 3 {
+		return nil, errors.New("numSegments must be >= 3")
+	}
+	vertices := make([]mgl32.Vec2, 0, numSegments)
+	step := (math.Pi * 2.0) / float64(numSegments)
+
+	for index := 0; index < numSegments; index++ {
+		angle := float64(startAngle) + float64(index)*step
+		p := mgl32.Vec2{radiusX * float32(math.Cos(angle)), radiusY * float32(math.Sin(angle))}.Add(center)
+		vertices = append(vertices, p)
+	}
+
+	return vertices, nil
+}
+
+// ArcToPolygon approximate an arc of circle going from startAngle to endAngle (counter-clockwise when endAngle is
+// bigger) with numSegments segments. It returns numSegments+1 points, the arc is not closed
+func ArcToPolygon(center mgl32.Vec2, radius, startAngle, endAngle float32, numSegments int) ([]mgl32.Vec2, error) {
+	if radius <= 0 {
+		return nil, errors.New("Radius cannot be <=0")
+	}
+	if numSegments < 1 {
+		return nil, errors.New("numSegments must be >= 1")
+	}
+	if startAngle == endAngle {
+		return nil, errors.New("startAngle and endAngle must be different")
+	}
+	vertices := make([]mgl32.Vec2, 0, numSegments+1)
+	step := float64(endAngle-startAngle) / float64(numSegments)
+
+	for index := 0; index <= numSegments; index++ {
+		angle := float64(startAngle) + float64(index)*step
+		p := mgl32.Vec2{radius * float32(math.Cos(angle)), radius * float32(math.Sin(angle))}.Add(center)
+		vertices = append(vertices, p)
+	}
+
+	return vertices, nil
+}
+
+// PieToPolygon approximate a pie slice: the center followed by the points of ArcToPolygon.
+// The result can be drawn as a triangle fan or passed to Triangulate
+func PieToPolygon(center mgl32.Vec2, radius, startAngle, endAngle float32, numSegments int) ([]mgl32.Vec2, error) {
+	arc, err := ArcToPolygon(center, radius, startAngle, endAngle, numSegments)
+	if err != nil {
+		return nil, err
+	}
+	return append([]mgl32.Vec2{center}, arc...), nil
+}
+
+// RoundedRectToPolygon approximate a rectangle with rounded corners, each corner made of cornerSegments segments.
+// The radius is clamped to half the shorter side, so big values produce a capsule or a circle.
+// The vertices are in counter-clockwise order (with Y pointing up), starting from the bottom right corner
+func RoundedRectToPolygon(min, max mgl32.Vec2, cornerRadius float32, cornerSegments int) ([]mgl32.Vec2, error) {
+	if max.X() <= min.X() || max.Y() <= min.Y() {
+		return nil, errors.New("max must be bigger than min on both axes")
+	}
+	if cornerRadius < 0 {
+		return nil, errors.New("cornerRadius cannot be <0")
+	}
+	if cornerSegments < 1 {
+		return nil, errors.New("cornerSegments must be >= 1")
+	}
+	halfSide := float32(math.Min(float64(max.X()-min.X()), float64(max.Y()-min.Y()))) / 2
+	if cornerRadius > halfSide {
+		cornerRadius = halfSide
+	}
+	if cornerRadius == 0 {
+		return []mgl32.Vec2{{max.X(), min.Y()}, max, {min.X(), max.Y()}, min}, nil
+	}
+
+	corners := []struct {
+		center     mgl32.Vec2
+		startAngle float32
+	}{
+		{mgl32.Vec2{max.X() - cornerRadius, min.Y() + cornerRadius}, -math.Pi / 2},
+		{mgl32.Vec2{max.X() - cornerRadius, max.Y() - cornerRadius}, 0},
+		{mgl32.Vec2{min.X() + cornerRadius, max.Y() - cornerRadius}, math.Pi / 2},
+		{mgl32.Vec2{min.X() + cornerRadius, min.Y() + cornerRadius}, math.Pi},
+	}
+	// When the radius has been clamped, adjacent arcs meet in the same point
+	samePoint := func(a, b mgl32.Vec2) bool {
+		return a.Sub(b).Len() < cornerRadius*1e-4
+	}
+	vertices := make([]mgl32.Vec2, 0, (cornerSegments+1)*4)
+	for _, corner := range corners {
+		arc, err := ArcToPolygon(corner.center, cornerRadius, corner.startAngle, corner.startAngle+math.Pi/2, cornerSegments)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range arc {
+			if len(vertices) > 0 && samePoint(p, vertices[len(vertices)-1]) {
+				continue
+			}
+			vertices = append(vertices, p)
+		}
+	}
+	if len(vertices) > 1 && samePoint(vertices[0], vertices[len(vertices)-1]) {
+		vertices = vertices[:len(vertices)-1]
+	}
+
+	return vertices, nil
+}
+
+// Lerp linearly interpolates between a and b. t=0 returns a, t=1 returns b
+func Lerp(a, b, t float32) float32 {
+	return a + (b-a)*t
+}
+
+// Clamp limits v to the range [min, max]
+func Clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Remap maps v from the range [inMin, inMax] to the range [outMin, outMax]. The result is not clamped
+func Remap(v, inMin, inMax, outMin, outMax float32) float32 {
+	if inMax == inMin {
+		return outMin
+	}
+	return outMin + (v-inMin)*(outMax-outMin)/(inMax-inMin)
+}
+
+// SmoothStep returns 0 below edge0, 1 above edge1 and a smooth Hermite interpolation in between, like GLSL smoothstep
+func SmoothStep(edge0, edge1, x float32) float32 {
+	if edge0 == edge1 {
+		if x < edge0 {
+			return 0
+		}
+		return 1
+	}
+	t := Clamp((x-edge0)/(edge1-edge0), 0, 1)
+	return t * t * (3 - 2*t)
+}
+
+// Lerp64 is the float64 version of Lerp
+func Lerp64(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// Clamp64 is the float64 version of Clamp
+func Clamp64(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// Remap64 is the float64 version of Remap
+func Remap64(v, inMin, inMax, outMin, outMax float64) float64 {
+	if inMax == inMin {
+		return outMin
+	}
+	return outMin + (v-inMin)*(outMax-outMin)/(inMax-inMin)
+}
+
+// SmoothStep64 is the float64 version of SmoothStep
+func SmoothStep64(edge0, edge1, x float64) float64 {
+	if edge0 == edge1 {
+		if x < edge0 {
+			return 0
+		}
+		return 1
+	}
+	t := Clamp64((x-edge0)/(edge1-edge0), 0, 1)
+	return t * t * (3 - 2*t)
+}
+
+// NextPowerOfTwo returns the smallest power of two greater than or equal to n. Values <= 1 return 1, values above
+// 2^30 return 2^30
+func NextPowerOfTwo(n int32) int32 {
+	power := int32(1)
+	for power < n && power < 1<<30 {
+		power <<= 1
+	}
+	return power
+}
+
+// NormalizeAngle wraps an angle in radians into the range (-Pi, Pi]
+func NormalizeAngle(radians float32) float32 {
+	return float32(normalizeAngle(float64(radians), math.Pi))
+}
+
+// AngleDifference returns the shortest signed rotation in radians going from one angle to the other,
+// e.g. from 350° to 10° is +20°
+func AngleDifference(from, to float32) float32 {
+	return NormalizeAngle(to - from)
+}
+
+// NormalizeAngleDegrees wraps an angle in degrees into the range (-180, 180].
+// Use mgl32.DegToRad and mgl32.RadToDeg to convert between the two units
+func NormalizeAngleDegrees(degrees float32) float32 {
+	return float32(normalizeAngle(float64(degrees), 180))
+}
+
+// AngleDifferenceDegrees returns the shortest signed rotation in degrees going from one angle to the other
+func AngleDifferenceDegrees(from, to float32) float32 {
+	return NormalizeAngleDegrees(to - from)
+}
+
+// normalizeAngle wraps an angle into (-halfTurn, halfTurn]
+func normalizeAngle(angle float64, halfTurn float64) float64 {
+	angle = math.Mod(angle+halfTurn, 2*halfTurn)
+	if angle <= 0 {
+		angle += 2 * halfTurn
+	}
+	return angle - halfTurn
+}
+
+// Reflect returns the incident vector mirrored on the surface having the given normal, which must be normalized
+func Reflect(incident, normal mgl32.Vec2) mgl32.Vec2 {
+	return incident.Sub(normal.Mul(2 * incident.Dot(normal)))
+}
+
+// Reflect3D returns the incident vector mirrored on the surface having the given normal, which must be normalized
+func Reflect3D(incident, normal mgl32.Vec3) mgl32.Vec3 {
+	return incident.Sub(normal.Mul(2 * incident.Dot(normal)))
+}
+
+// ProjectOnto returns the projection of a on the direction of b. A zero b returns a zero vector
+func ProjectOnto(a, b mgl32.Vec2) mgl32.Vec2 {
+	lengthSquared := b.Dot(b)
+	if lengthSquared == 0 {
+		return mgl32.Vec2{}
+	}
+	return b.Mul(a.Dot(b) / lengthSquared)
+}
+
+// ProjectOnto3D returns the projection of a on the direction of b. A zero b returns a zero vector
+func ProjectOnto3D(a, b mgl32.Vec3) mgl32.Vec3 {
+	lengthSquared := b.Dot(b)
+	if lengthSquared == 0 {
+		return mgl32.Vec3{}
+	}
+	return b.Mul(a.Dot(b) / lengthSquared)
+}
+
+// Perpendicular returns the vector rotated by 90° counter-clockwise (with Y pointing up)
+func Perpendicular(v mgl32.Vec2) mgl32.Vec2 {
+	return mgl32.Vec2{-v.Y(), v.X()}
+}
+
+// LookRotation returns the orientation facing forward, with its up direction as close as possible to up. Like the
+// OpenGL cameras the object is assumed to face -Z, so the rotation maps -Z onto forward and +Y towards up.
+// When up is parallel to forward another up direction is picked, a zero forward returns the identity
+func LookRotation(forward, up mgl32.Vec3) mgl32.Quat {
+	if forward.Len() == 0 {
+		return mgl32.QuatIdent()
+	}
+	forward = forward.Normalize()
+	right := forward.Cross(up)
+	if right.Len() < 1e-6 {
+		right = forward.Cross(mgl32.Vec3{0, 0, 1})
+		if right.Len() < 1e-6 {
+			right = forward.Cross(mgl32.Vec3{1, 0, 0})
+		}
+	}
+	right = right.Normalize()
+	up = right.Cross(forward)
+	return mgl32.Mat4ToQuat(mgl32.Mat3FromCols(right, up, forward.Mul(-1)).Mat4()).Normalize()
+}
+
+// FromToRotation returns the shortest rotation that turns the direction from into the direction to. Opposite
+// directions give a half turn around an axis perpendicular to from, zero vectors return the identity
+func FromToRotation(from, to mgl32.Vec3) mgl32.Quat {
+	if from.Len() == 0 || to.Len() == 0 {
+		return mgl32.QuatIdent()
+	}
+	from = from.Normalize()
+	to = to.Normalize()
+	cosTheta := from.Dot(to)
+	if cosTheta < -1+1e-6 {
+		axis := mgl32.Vec3{1, 0, 0}.Cross(from)
+		if axis.Len() < 1e-3 {
+			axis = mgl32.Vec3{0, 1, 0}.Cross(from)
+		}
+		return mgl32.QuatRotate(math.Pi, axis.Normalize())
+	}
+	// The quaternion of twice the rotation is (cosTheta, from x to), halving it means adding the identity
+	return mgl32.Quat{W: 1 + cosTheta, V: from.Cross(to)}.Normalize()
+}
+
 // GetBoundingBox returns the top left and the bottom right points of the 2D box bounding all the points passed.
+// An empty slice has no bounds and returns two zero vectors
 func GetBoundingBox(points []mgl32.Vec2) (mgl32.Vec2, mgl32.Vec2) {
+	if len(points) == 0 {
+		return mgl32.Vec2{}, mgl32.Vec2{}
+	}
 	var minX, minY, maxX, maxY float32
 	minX = math.MaxFloat32
 	minY = math.MaxFloat32
@@ -54,6 +403,474 @@ func GetBoundingBox(points []mgl32.Vec2) (mgl32.Vec2, mgl32.Vec2) {
 	return mgl32.Vec2{minX, minY}, mgl32.Vec2{maxX, maxY}
 }
 
+// PointInPolygon tests if a point is inside a polygon using the even-odd rule, so it works with concave and
+// self-intersecting polygons too. Points lying exactly on an edge are considered inside.
+// Polygons with fewer than 3 points have no inside and always return false
+func PointInPolygon(p mgl32.Vec2, polygon []mgl32.Vec2) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[j], polygon[i]
+		if pointOnSegment(p, a, b) {
+			return true
+		}
+		// Cast a ray towards +X, each edge crossing it flips the state. The half-open test on Y makes sure a vertex
+		// shared by two edges is counted once
+		if (a.Y() > p.Y()) != (b.Y() > p.Y()) {
+			crossX := a.X() + (p.Y()-a.Y())*(b.X()-a.X())/(b.Y()-a.Y())
+			if p.X() < crossX {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// pointOnSegment tests if p lies on the segment a-b
+func pointOnSegment(p, a, b mgl32.Vec2) bool {
+	ab := b.Sub(a)
+	ap := p.Sub(a)
+	cross := ab.X()*ap.Y() - ab.Y()*ap.X()
+	if math.Abs(float64(cross)) > 1e-6*float64(ab.Len()+1) {
+		return false
+	}
+	dot := ap.Dot(ab)
+	return dot >= 0 && dot <= ab.Dot(ab)
+}
+
+// PolygonSignedArea returns the area of a polygon using the shoelace formula. The area is positive when the vertices
+// are in counter-clockwise order (with Y pointing up) and negative when they are clockwise.
+// Polygons with fewer than 3 points have no area
+func PolygonSignedArea(polygon []mgl32.Vec2) float32 {
+	if len(polygon) < 3 {
+		return 0
+	}
+	var area float32
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		area += polygon[j].X()*polygon[i].Y() - polygon[i].X()*polygon[j].Y()
+	}
+	return area / 2
+}
+
+// IsClockwise reports whether the vertices of a polygon are in clockwise order (with Y pointing up), based on the
+// sign of PolygonSignedArea. Degenerate polygons with no area are not clockwise
+func IsClockwise(polygon []mgl32.Vec2) bool {
+	return PolygonSignedArea(polygon) < 0
+}
+
+// EnsureCounterClockwise reverses the vertices of a clockwise polygon in place, and returns it
+func EnsureCounterClockwise(polygon []mgl32.Vec2) []mgl32.Vec2 {
+	if IsClockwise(polygon) {
+		for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+			polygon[i], polygon[j] = polygon[j], polygon[i]
+		}
+	}
+	return polygon
+}
+
+// PolygonArea returns the area of a polygon, regardless of its winding
+func PolygonArea(polygon []mgl32.Vec2) float32 {
+	return float32(math.Abs(float64(PolygonSignedArea(polygon))))
+}
+
+// PolygonCentroid returns the center of mass of a non self-intersecting polygon, of any winding.
+// For degenerate polygons (fewer than 3 points or no area) it returns the average of the points
+func PolygonCentroid(polygon []mgl32.Vec2) mgl32.Vec2 {
+	if len(polygon) == 0 {
+		return mgl32.Vec2{}
+	}
+	area := PolygonSignedArea(polygon)
+	if area == 0 {
+		var sum mgl32.Vec2
+		for _, p := range polygon {
+			sum = sum.Add(p)
+		}
+		return sum.Mul(1 / float32(len(polygon)))
+	}
+
+	var cx, cy float32
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[j], polygon[i]
+		cross := a.X()*b.Y() - b.X()*a.Y()
+		cx += (a.X() + b.X()) * cross
+		cy += (a.Y() + b.Y()) * cross
+	}
+	return mgl32.Vec2{cx / (6 * area), cy / (6 * area)}
+}
+
+// ConvexHull returns the convex hull of a set of points in counter-clockwise order (with Y pointing up), starting
+// from the point with the lowest X (and lowest Y among equals). It uses Andrew's monotone chain, O(n log n).
+// Duplicated points and points lying on a hull edge are dropped, only the corners are returned.
+// When there are fewer than 3 unique points, the unique points are returned as they are
+func ConvexHull(points []mgl32.Vec2) []mgl32.Vec2 {
+	sorted := make([]mgl32.Vec2, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X() != sorted[j].X() {
+			return sorted[i].X() < sorted[j].X()
+		}
+		return sorted[i].Y() < sorted[j].Y()
+	})
+
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	hull := make([]mgl32.Vec2, 0, len(unique)+1)
+	// Lower chain
+	for _, p := range unique {
+		for len(hull) >= 2 && cross2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// Upper chain
+	lowerSize := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lowerSize && cross2D(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The last point is the first one again
+	return hull[:len(hull)-1]
+}
+
+// Triangulate splits a simple polygon (convex or concave, of any winding) into triangles using ear clipping, O(n^2).
+// It returns the triangles as index triples into polygon, in counter-clockwise order, ready to be used with an
+// indexed Mesh. Self-intersecting polygons are not supported and return an error
+func Triangulate(polygon []mgl32.Vec2) ([]uint32, error) {
+	if len(polygon) < 3 {
+		return nil, errors.New("a polygon needs at least 3 points to be triangulated")
+	}
+	if polygonSelfIntersects(polygon) {
+		return nil, errors.New("cannot triangulate a self-intersecting polygon")
+	}
+
+	remaining := make([]uint32, len(polygon))
+	for i := range remaining {
+		remaining[i] = uint32(i)
+	}
+	// Walk a clockwise polygon backwards instead of reversing it, the indices must refer to the caller's order
+	if IsClockwise(polygon) {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+
+	indices := make([]uint32, 0, (len(polygon)-2)*3)
+	for len(remaining) > 3 {
+		earFound := false
+		for i := range remaining {
+			prev := remaining[(i+len(remaining)-1)%len(remaining)]
+			curr := remaining[i]
+			next := remaining[(i+1)%len(remaining)]
+			turn := cross2D(polygon[prev], polygon[curr], polygon[next])
+			if turn == 0 {
+				// Collinear vertices don't contribute any area, drop them
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				earFound = true
+				break
+			}
+			if turn < 0 || !isEar(polygon, remaining, prev, curr, next) {
+				continue
+			}
+			indices = append(indices, prev, curr, next)
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			earFound = true
+			break
+		}
+		if !earFound {
+			return nil, errors.New("cannot triangulate the polygon, no ear found")
+		}
+	}
+	if cross2D(polygon[remaining[0]], polygon[remaining[1]], polygon[remaining[2]]) != 0 {
+		indices = append(indices, remaining...)
+	}
+	return indices, nil
+}
+
+// cross2D returns the Z component of (a-o)x(b-o), positive when o->a->b turns counter-clockwise
+func cross2D(o, a, b mgl32.Vec2) float32 {
+	return (a.X()-o.X())*(b.Y()-o.Y()) - (a.Y()-o.Y())*(b.X()-o.X())
+}
+
+// isEar tests that no other vertex of the polygon lies inside the convex corner prev-curr-next
+func isEar(polygon []mgl32.Vec2, remaining []uint32, prev, curr, next uint32) bool {
+	a, b, c := polygon[prev], polygon[curr], polygon[next]
+	for _, index := range remaining {
+		if index == prev || index == curr || index == next {
+			continue
+		}
+		p := polygon[index]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if cross2D(a, b, p) >= 0 && cross2D(b, c, p) >= 0 && cross2D(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// polygonSelfIntersects tests if any two non adjacent edges of the polygon touch each other, O(n^2)
+func polygonSelfIntersects(polygon []mgl32.Vec2) bool {
+	n := len(polygon)
+	for i := 0; i < n; i++ {
+		a0, a1 := polygon[i], polygon[(i+1)%n]
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				// First and last edge share a vertex
+				continue
+			}
+			if segmentsTouch(a0, a1, polygon[j], polygon[(j+1)%n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsTouch tests if the segments a0-a1 and b0-b1 have at least a point in common
+func segmentsTouch(a0, a1, b0, b1 mgl32.Vec2) bool {
+	d1 := cross2D(b0, b1, a0)
+	d2 := cross2D(b0, b1, a1)
+	d3 := cross2D(a0, a1, b0)
+	d4 := cross2D(a0, a1, b1)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && pointOnSegment(a0, b0, b1)) ||
+		(d2 == 0 && pointOnSegment(a1, b0, b1)) ||
+		(d3 == 0 && pointOnSegment(b0, a0, a1)) ||
+		(d4 == 0 && pointOnSegment(b1, a0, a1))
+}
+
+// GetBoundingBox3D returns the min and the max corners of the 3D box bounding all the points passed.
+// An empty slice has no bounds and returns two zero vectors
+func GetBoundingBox3D(points []mgl32.Vec3) (mgl32.Vec3, mgl32.Vec3) {
+	if len(points) == 0 {
+		return mgl32.Vec3{}, mgl32.Vec3{}
+	}
+	min := points[0]
+	max := points[0]
+	for _, p := range points[1:] {
+		for axis := 0; axis < 3; axis++ {
+			if p[axis] < min[axis] {
+				min[axis] = p[axis]
+			}
+			if p[axis] > max[axis] {
+				max[axis] = p[axis]
+			}
+		}
+	}
+
+	return min, max
+}
+
+// GetBoundingCircle returns the smallest circle enclosing all the points passed. It uses the randomized incremental
+// version of Welzl's algorithm, expected O(n). The shuffle uses a fixed seed, so the result is deterministic.
+// A single point returns a circle of radius 0 centered on it, an empty slice returns a zero circle
+func GetBoundingCircle(points []mgl32.Vec2) (center mgl32.Vec2, radius float32) {
+	if len(points) == 0 {
+		return mgl32.Vec2{}, 0
+	}
+	shuffled := make([]mgl64.Vec2, len(points))
+	for i, p := range points {
+		shuffled[i] = mgl64.Vec2{float64(p.X()), float64(p.Y())}
+	}
+	random := rand.New(rand.NewSource(1))
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	c := shuffled[0]
+	var r float64
+	inside := func(p mgl64.Vec2) bool {
+		return p.Sub(c).Len() <= r*(1+1e-9)+1e-9
+	}
+	for i := 1; i < len(shuffled); i++ {
+		if inside(shuffled[i]) {
+			continue
+		}
+		// shuffled[i] is on the boundary of the circle enclosing the first i+1 points
+		c, r = shuffled[i], 0
+		for j := 0; j < i; j++ {
+			if inside(shuffled[j]) {
+				continue
+			}
+			c, r = circleFrom2Points(shuffled[i], shuffled[j])
+			for k := 0; k < j; k++ {
+				if !inside(shuffled[k]) {
+					c, r = circleFrom3Points(shuffled[i], shuffled[j], shuffled[k])
+				}
+			}
+		}
+	}
+
+	return mgl32.Vec2{float32(c.X()), float32(c.Y())}, float32(r)
+}
+
+// circleFrom2Points returns the circle having a-b as diameter
+func circleFrom2Points(a, b mgl64.Vec2) (mgl64.Vec2, float64) {
+	center := a.Add(b).Mul(0.5)
+	return center, center.Sub(a).Len()
+}
+
+// circleFrom3Points returns the circle passing through a, b and c. For collinear points it returns the circle
+// having the two farthest points as diameter
+func circleFrom3Points(a, b, c mgl64.Vec2) (mgl64.Vec2, float64) {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	d := 2 * (ab.X()*ac.Y() - ab.Y()*ac.X())
+	if d == 0 {
+		center, radius := circleFrom2Points(a, b)
+		if otherCenter, otherRadius := circleFrom2Points(a, c); otherRadius > radius {
+			center, radius = otherCenter, otherRadius
+		}
+		if otherCenter, otherRadius := circleFrom2Points(b, c); otherRadius > radius {
+			center, radius = otherCenter, otherRadius
+		}
+		return center, radius
+	}
+	abLen := ab.Dot(ab)
+	acLen := ac.Dot(ac)
+	offset := mgl64.Vec2{
+		(ac.Y()*abLen - ab.Y()*acLen) / d,
+		(ab.X()*acLen - ac.X()*abLen) / d,
+	}
+	return a.Add(offset), offset.Len()
+}
+
+// TransformPoints returns a new slice with the points transformed by an affine 2D matrix, each point being
+// treated as (x, y, 1)
+func TransformPoints(points []mgl32.Vec2, m mgl32.Mat3) []mgl32.Vec2 {
+	transformed := make([]mgl32.Vec2, len(points))
+	copy(transformed, points)
+	TransformPointsInPlace(transformed, m)
+	return transformed
+}
+
+// TransformPointsInPlace transforms the points by an affine 2D matrix without allocating, each point being
+// treated as (x, y, 1)
+func TransformPointsInPlace(points []mgl32.Vec2, m mgl32.Mat3) {
+	for i, p := range points {
+		points[i] = m.Mul3x1(mgl32.Vec3{p.X(), p.Y(), 1}).Vec2()
+	}
+}
+
+// Transform2D builds the 2D matrix scaling, then rotating (in radians) and finally translating a point: T*R*S
+func Transform2D(translation mgl32.Vec2, rotation float32, scale mgl32.Vec2) mgl32.Mat3 {
+	return mgl32.Translate2D(translation.X(), translation.Y()).
+		Mul3(mgl32.HomogRotate2D(rotation)).
+		Mul3(mgl32.Scale2D(scale.X(), scale.Y()))
+}
+
+// DecomposeTransform2D extracts translation, rotation and scale from a matrix built by Transform2D.
+// A mirroring matrix is returned with a negative Y scale. Matrices with shear can't be decomposed exactly
+func DecomposeTransform2D(m mgl32.Mat3) (translation mgl32.Vec2, rotation float32, scale mgl32.Vec2) {
+	translation = mgl32.Vec2{m[6], m[7]}
+	scaleX := mgl32.Vec2{m[0], m[1]}.Len()
+	scaleY := mgl32.Vec2{m[3], m[4]}.Len()
+	if m[0]*m[4]-m[1]*m[3] < 0 {
+		scaleY = -scaleY
+	}
+	rotation = float32(math.Atan2(float64(m[1]), float64(m[0])))
+	return translation, rotation, mgl32.Vec2{scaleX, scaleY}
+}
+
+// SimplifyPolyline reduces the number of points of a polyline with the Douglas-Peucker algorithm. The endpoints are
+// always kept, the other points are dropped when they are closer than epsilon to the simplified polyline.
+// With epsilon <= 0 or fewer than 3 points the input is returned unchanged
+func SimplifyPolyline(points []mgl32.Vec2, epsilon float32) []mgl32.Vec2 {
+	if epsilon <= 0 || len(points) < 3 {
+		return points
+	}
+	keep := make([]bool, len(points))
+	keep[0] = true
+	keep[len(points)-1] = true
+	simplifyRange(points, 0, len(points)-1, epsilon, keep)
+
+	simplified := make([]mgl32.Vec2, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// simplifyRange marks the points to keep between first and last, both excluded
+func simplifyRange(points []mgl32.Vec2, first, last int, epsilon float32, keep []bool) {
+	var maxDistance float32
+	farthest := -1
+	for i := first + 1; i < last; i++ {
+		distance := DistanceToSegment(points[i], points[first], points[last])
+		if distance > maxDistance {
+			maxDistance = distance
+			farthest = i
+		}
+	}
+	if farthest < 0 || maxDistance <= epsilon {
+		return
+	}
+	keep[farthest] = true
+	simplifyRange(points, first, farthest, epsilon, keep)
+	simplifyRange(points, farthest, last, epsilon, keep)
+}
+
+// OffsetPolygon moves every edge of a polygon along its normal and rebuilds the vertices from the intersections of
+// the moved edges. A positive distance grows the polygon, a negative one shrinks it, regardless of its winding.
+// The result has the same number of vertices. Corners are not rounded nor beveled, so very sharp corners produce
+// long spikes, and insetting a concave corner (or by more than the polygon thickness) can make the result
+// self-intersect: this is not resolved here.
+// Polygons with fewer than 3 points are returned unchanged
+func OffsetPolygon(polygon []mgl32.Vec2, distance float32) []mgl32.Vec2 {
+	n := len(polygon)
+	if n < 3 {
+		return polygon
+	}
+	// With counter-clockwise winding the outward normal of an edge is on its right
+	if PolygonSignedArea(polygon) < 0 {
+		distance = -distance
+	}
+	edgeNormal := func(a, b mgl32.Vec2) mgl32.Vec2 {
+		edge := b.Sub(a)
+		if edge.Len() == 0 {
+			return mgl32.Vec2{}
+		}
+		return Perpendicular(edge).Mul(-1).Normalize()
+	}
+
+	offset := make([]mgl32.Vec2, n)
+	for i := range polygon {
+		prev := polygon[(i+n-1)%n]
+		curr := polygon[i]
+		next := polygon[(i+1)%n]
+		prevNormal := edgeNormal(prev, curr).Mul(distance)
+		nextNormal := edgeNormal(curr, next).Mul(distance)
+
+		point, ok := lineIntersection(prev.Add(prevNormal), curr.Add(prevNormal), curr.Add(nextNormal), next.Add(nextNormal))
+		if !ok {
+			// Parallel edges, the vertex just moves along the shared normal. A zero length edge has no normal, the
+			// one of the other edge is used
+			if nextNormal == (mgl32.Vec2{}) {
+				nextNormal = prevNormal
+			}
+			point = curr.Add(nextNormal)
+		}
+		offset[i] = point
+	}
+	return offset
+}
+
 func Mat4From64to32Bits(mat mgl64.Mat4) mgl32.Mat4 {
 	return mgl32.Mat4{
 		float32(mat[0]),
@@ -74,3 +891,75 @@ func Mat4From64to32Bits(mat mgl64.Mat4) mgl32.Mat4 {
 		float32(mat[15]),
 	}
 }
+
+// Mat4From32to64Bits widens a 32-bit matrix to 64 bits, the inverse of Mat4From64to32Bits
+func Mat4From32to64Bits(mat mgl32.Mat4) mgl64.Mat4 {
+	return mgl64.Mat4{
+		float64(mat[0]),
+		float64(mat[1]),
+		float64(mat[2]),
+		float64(mat[3]),
+		float64(mat[4]),
+		float64(mat[5]),
+		float64(mat[6]),
+		float64(mat[7]),
+		float64(mat[8]),
+		float64(mat[9]),
+		float64(mat[10]),
+		float64(mat[11]),
+		float64(mat[12]),
+		float64(mat[13]),
+		float64(mat[14]),
+		float64(mat[15]),
+	}
+}
+
+// Mat3From64to32Bits narrows a 64-bit 3x3 matrix to 32 bits
+func Mat3From64to32Bits(mat mgl64.Mat3) mgl32.Mat3 {
+	return mgl32.Mat3{
+		float32(mat[0]),
+		float32(mat[1]),
+		float32(mat[2]),
+		float32(mat[3]),
+		float32(mat[4]),
+		float32(mat[5]),
+		float32(mat[6]),
+		float32(mat[7]),
+		float32(mat[8]),
+	}
+}
+
+// Mat3From32to64Bits widens a 32-bit 3x3 matrix to 64 bits
+func Mat3From32to64Bits(mat mgl32.Mat3) mgl64.Mat3 {
+	return mgl64.Mat3{
+		float64(mat[0]),
+		float64(mat[1]),
+		float64(mat[2]),
+		float64(mat[3]),
+		float64(mat[4]),
+		float64(mat[5]),
+		float64(mat[6]),
+		float64(mat[7]),
+		float64(mat[8]),
+	}
+}
+
+// Vec2From64to32Bits narrows a 64-bit vector to 32 bits
+func Vec2From64to32Bits(vec mgl64.Vec2) mgl32.Vec2 {
+	return mgl32.Vec2{float32(vec[0]), float32(vec[1])}
+}
+
+// Vec2From32to64Bits widens a 32-bit vector to 64 bits
+func Vec2From32to64Bits(vec mgl32.Vec2) mgl64.Vec2 {
+	return mgl64.Vec2{float64(vec[0]), float64(vec[1])}
+}
+
+// Vec3From64to32Bits narrows a 64-bit vector to 32 bits
+func Vec3From64to32Bits(vec mgl64.Vec3) mgl32.Vec3 {
+	return mgl32.Vec3{float32(vec[0]), float32(vec[1]), float32(vec[2])}
+}
+
+// Vec3From32to64Bits widens a 32-bit vector to 64 bits
+func Vec3From32to64Bits(vec mgl32.Vec3) mgl64.Vec3 {
+	return mgl64.Vec3{float64(vec[0]), float64(vec[1]), float64(vec[2])}
+}
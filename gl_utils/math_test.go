@@ -0,0 +1,203 @@
+package gl_utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+func TestCircleToPolygonClosingGap(t *testing.T) {
+	const segments = 1000
+	const radius = 100
+	vertices, err := CircleToPolygon(mgl32.Vec2{10, -20}, radius, segments, 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != segments || cap(vertices) != segments {
+		t.Fatalf("got %d vertices with capacity %d, expected %d", len(vertices), cap(vertices), segments)
+	}
+	// The closing edge must be as long as all the others
+	side := 2 * radius * math.Sin(math.Pi/segments)
+	for i := range vertices {
+		edge := float64(vertices[(i+1)%segments].Sub(vertices[i]).Len())
+		if math.Abs(edge-side) > 1e-3 {
+			t.Fatalf("edge %d is %f long, expected %f", i, edge, side)
+		}
+	}
+}
+
+func TestMat4From32to64BitsRoundTrip(t *testing.T) {
+	mat := mgl32.Mat4{
+		1, -2.5, 3.25, 0,
+		1e-7, 123456.78, -0.1, 0.3,
+		math.MaxFloat32, -math.MaxFloat32, math.SmallestNonzeroFloat32, 1.0 / 3,
+		0, 0, 42, 1,
+	}
+	wide := Mat4From32to64Bits(mat)
+	for i := range mat {
+		if wide[i] != float64(mat[i]) {
+			t.Errorf("component %d widened to %v, expected %v", i, wide[i], float64(mat[i]))
+		}
+	}
+	if back := Mat4From64to32Bits(wide); back != mat {
+		t.Errorf("round trip gives %v, expected %v", back, mat)
+	}
+	if identity := Mat4From32to64Bits(mgl32.Ident4()); identity != mgl64.Ident4() {
+		t.Errorf("identity widened to %v", identity)
+	}
+}
+
+func TestFromToRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to mgl32.Vec3
+		expected mgl32.Quat
+	}{
+		{"+Z to +X", mgl32.Vec3{0, 0, 1}, mgl32.Vec3{1, 0, 0}, mgl32.QuatRotate(math.Pi/2, mgl32.Vec3{0, 1, 0})},
+		{"parallel", mgl32.Vec3{0, 2, 0}, mgl32.Vec3{0, 5, 0}, mgl32.QuatIdent()},
+		{"zero vector", mgl32.Vec3{}, mgl32.Vec3{1, 0, 0}, mgl32.QuatIdent()},
+	}
+	for _, test := range tests {
+		if got := FromToRotation(test.from, test.to); !got.OrientationEqualThreshold(test.expected, 1e-5) {
+			t.Errorf("%s: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestFromToRotationAntiparallel(t *testing.T) {
+	for _, from := range []mgl32.Vec3{{0, 0, 1}, {1, 0, 0}, {0, -3, 0}, {1, 2, 3}} {
+		to := from.Mul(-1)
+		rotation := FromToRotation(from, to)
+		if got := rotation.Rotate(from); !near3(got, to) {
+			t.Errorf("%v: rotated to %v, expected %v", from, got, to)
+		}
+		// A half turn has no real part, and its axis is perpendicular to from
+		if math.Abs(float64(rotation.W)) > 1e-5 || math.Abs(float64(rotation.V.Dot(from))) > 1e-5 {
+			t.Errorf("%v: %v is not a half turn around a perpendicular axis", from, rotation)
+		}
+	}
+}
+
+func TestLookRotation(t *testing.T) {
+	// Facing +X: -Z turns into +X, +Y stays up, which is a quarter turn clockwise around Y seen from above
+	rotation := LookRotation(mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0})
+	if expected := mgl32.QuatRotate(-math.Pi/2, mgl32.Vec3{0, 1, 0}); !rotation.OrientationEqualThreshold(expected, 1e-5) {
+		t.Errorf("got %v, expected %v", rotation, expected)
+	}
+	if forward := rotation.Rotate(mgl32.Vec3{0, 0, -1}); !near3(forward, mgl32.Vec3{1, 0, 0}) {
+		t.Errorf("-Z rotated to %v, expected +X", forward)
+	}
+	// up parallel to forward still gives a valid rotation
+	rotation = LookRotation(mgl32.Vec3{0, 3, 0}, mgl32.Vec3{0, 1, 0})
+	if forward := rotation.Rotate(mgl32.Vec3{0, 0, -1}); !near3(forward, mgl32.Vec3{0, 1, 0}) {
+		t.Errorf("-Z rotated to %v, expected +Y", forward)
+	}
+}
+
+// near3 compares two vectors with an absolute tolerance, mgl32 uses a relative one that never matches zero components
+func near3(a, b mgl32.Vec3) bool {
+	return a.Sub(b).Len() < 1e-5
+}
+
+func TestReflect(t *testing.T) {
+	half := float32(math.Sqrt2 / 2)
+	tests := []struct {
+		incident, normal, expected mgl32.Vec2
+	}{
+		{mgl32.Vec2{1, -1}, mgl32.Vec2{0, 1}, mgl32.Vec2{1, 1}},
+		{mgl32.Vec2{3, 4}, mgl32.Vec2{1, 0}, mgl32.Vec2{-3, 4}},
+		{mgl32.Vec2{1, 0}, mgl32.Vec2{half, half}, mgl32.Vec2{0, -1}},
+		{mgl32.Vec2{2, 0}, mgl32.Vec2{0, 1}, mgl32.Vec2{2, 0}},
+	}
+	for _, test := range tests {
+		if got := Reflect(test.incident, test.normal); got.Sub(test.expected).Len() > 1e-6 {
+			t.Errorf("Reflect(%v, %v) = %v, expected %v", test.incident, test.normal, got, test.expected)
+		}
+	}
+	if got := Reflect3D(mgl32.Vec3{1, -2, 3}, mgl32.Vec3{0, 1, 0}); got != (mgl32.Vec3{1, 2, 3}) {
+		t.Errorf("Reflect3D = %v, expected [1 2 3]", got)
+	}
+}
+
+func TestProjectOnto(t *testing.T) {
+	tests := []struct {
+		a, b, expected mgl32.Vec2
+	}{
+		{mgl32.Vec2{3, 4}, mgl32.Vec2{2, 0}, mgl32.Vec2{3, 0}},
+		{mgl32.Vec2{1, 3}, mgl32.Vec2{1, 1}, mgl32.Vec2{2, 2}},
+		{mgl32.Vec2{2, 2}, mgl32.Vec2{1, -1}, mgl32.Vec2{0, 0}},
+		{mgl32.Vec2{2, 2}, mgl32.Vec2{0, 0}, mgl32.Vec2{0, 0}},
+	}
+	for _, test := range tests {
+		if got := ProjectOnto(test.a, test.b); got != test.expected {
+			t.Errorf("ProjectOnto(%v, %v) = %v, expected %v", test.a, test.b, got, test.expected)
+		}
+	}
+	if got := ProjectOnto3D(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{0, 0, 5}); got != (mgl32.Vec3{0, 0, 3}) {
+		t.Errorf("ProjectOnto3D = %v, expected [0 0 3]", got)
+	}
+	if got := ProjectOnto3D(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{}); got != (mgl32.Vec3{}) {
+		t.Errorf("ProjectOnto3D on a zero vector = %v, expected [0 0 0]", got)
+	}
+}
+
+func TestPerpendicular(t *testing.T) {
+	if got := Perpendicular(mgl32.Vec2{1, 0}); got != (mgl32.Vec2{0, 1}) {
+		t.Errorf("Perpendicular([1 0]) = %v, expected [0 1]", got)
+	}
+	if got := Perpendicular(mgl32.Vec2{2, 3}); got != (mgl32.Vec2{-3, 2}) {
+		t.Errorf("Perpendicular([2 3]) = %v, expected [-3 2]", got)
+	}
+}
+
+// near2 compares two vectors with a tolerance relative to the length of the expected one
+func near2(a, b mgl32.Vec2, tolerance float32) bool {
+	return a.Sub(b).Len() <= tolerance*float32(math.Max(1, float64(b.Len())))
+}
+
+func TestOffsetPolygon(t *testing.T) {
+	square := func(size float32) []mgl32.Vec2 {
+		return []mgl32.Vec2{{0, 0}, {size, 0}, {size, size}, {0, size}}
+	}
+	for _, size := range []float32{1e-5, 1, 1e4} {
+		offset := OffsetPolygon(square(size), size/10)
+		outside, far := -size/10, size*1.1
+		expected := []mgl32.Vec2{{outside, outside}, {far, outside}, {far, far}, {outside, far}}
+		for i := range expected {
+			if !near2(offset[i], expected[i], 1e-4*size) {
+				t.Errorf("square of %v: corner %d at %v, expected %v", size, i, offset[i], expected[i])
+			}
+		}
+	}
+
+	// An almost straight vertex on the bottom edge moves along the normal instead of shooting away
+	bent := []mgl32.Vec2{{0, 0}, {1, -1e-7}, {2, 0}, {2, 2}, {0, 2}}
+	if got := OffsetPolygon(bent, 0.1)[1]; !near2(got, mgl32.Vec2{1, -0.1}, 1e-4) {
+		t.Errorf("almost straight vertex at %v, expected {1 -0.1}", got)
+	}
+
+	// The vertices around a zero length edge still move by the distance
+	duplicated := []mgl32.Vec2{{0, 0}, {0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	for i, got := range OffsetPolygon(duplicated, 0.1)[:2] {
+		if moved := got.Sub(duplicated[i]).Len(); moved < 0.1-1e-6 || moved > 0.15 {
+			t.Errorf("vertex %d next to the zero length edge moved by %v to %v", i, moved, got)
+		}
+	}
+}
+
+func TestSegmentIntersect(t *testing.T) {
+	for _, size := range []float32{1e-5, 1, 1e4} {
+		point, ok := SegmentIntersect(mgl32.Vec2{0, 0}, mgl32.Vec2{size, size}, mgl32.Vec2{0, size}, mgl32.Vec2{size, 0})
+		if !ok || !near2(point, mgl32.Vec2{size / 2, size / 2}, 1e-5*size) {
+			t.Errorf("diagonals of a square of %v: got %v, %v, expected the center", size, point, ok)
+		}
+	}
+	if _, ok := SegmentIntersect(mgl32.Vec2{0, 0}, mgl32.Vec2{1, 0}, mgl32.Vec2{0, 1}, mgl32.Vec2{1, 1}); ok {
+		t.Error("parallel segments reported as intersecting")
+	}
+	if _, ok := SegmentIntersect(mgl32.Vec2{0, 0}, mgl32.Vec2{1, 0}, mgl32.Vec2{2, -1}, mgl32.Vec2{2, 1}); ok {
+		t.Error("segments crossing beyond their ends reported as intersecting")
+	}
+}
@@ -0,0 +1,273 @@
+package gl_utils
+
+import (
+	"image"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// VertexAttribute describes where a shader input is stored inside interleaved vertex data.
+// Size, Offset and Stride are expressed in number of float32 (e.g. {Location: 1, Size: 2, Offset: 3, Stride: 5} for
+// the UVs of x,y,z,u,v vertices). A Stride of 0 means the attribute is tightly packed
+type VertexAttribute struct {
+	Location uint32
+	Size     int32
+	Offset   int
+	Stride   int32
+}
+
+// Mesh a vertex array object with its vertex buffer
+type Mesh struct {
+	vaoID       uint32
+	vboVertices uint32
+	eboIndices  uint32
+	// Per-instance attributes, see SetInstanceData
+	vboInstances uint32
+	vertexCount  int32
+	indexCount   int32
+	// Usage hint, capacity of the vertex buffer and size of a vertex, in float32, used by UpdateVertices
+	usage          uint32
+	vertexCapacity int
+	vertexSize     int
+}
+
+// NewMesh uploads interleaved vertex data and configures the vertex attributes described
+func NewMesh(vertices []float32, attributes []VertexAttribute) *Mesh {
+	return NewIndexedMesh(vertices, nil, attributes)
+}
+
+// NewIndexedMesh uploads interleaved vertex data together with the indices of the vertices to draw.
+// When indices is empty the vertices are drawn in order, like NewMesh does
+func NewIndexedMesh(vertices []float32, indices []uint32, attributes []VertexAttribute) *Mesh {
+	return NewMeshWithUsage(vertices, indices, attributes, gl.STATIC_DRAW)
+}
+
+// NewMeshWithUsage is like NewIndexedMesh, with a hint of how often the vertices are going to change:
+// gl.STATIC_DRAW for geometry that never changes, gl.DYNAMIC_DRAW for the one updated with UpdateVertices,
+// gl.STREAM_DRAW when the vertices are replaced at every frame
+func NewMeshWithUsage(vertices []float32, indices []uint32, attributes []VertexAttribute, usage uint32) *Mesh {
+	m := &Mesh{
+		indexCount:     int32(len(indices)),
+		usage:          usage,
+		vertexCapacity: len(vertices),
+		vertexSize:     floatsPerVertex(attributes),
+	}
+	m.vertexCount = int32(len(vertices) / m.vertexSize)
+	gl.GenVertexArrays(1, &m.vaoID)
+	gl.BindVertexArray(m.vaoID)
+
+	gl.GenBuffers(1, &m.vboVertices)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vboVertices)
+	// An empty buffer is valid, e.g. a tile map with no tiles or vertices to be filled with UpdateVertices
+	var data unsafe.Pointer
+	if len(vertices) > 0 {
+		data = gl.Ptr(vertices)
+	}
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*Float32Size, data, usage)
+	for _, attribute := range attributes {
+		gl.EnableVertexAttribArray(attribute.Location)
+		gl.VertexAttribPointer(
+			attribute.Location, attribute.Size, gl.FLOAT, false,
+			attribute.Stride*Float32Size, gl.PtrOffset(attribute.Offset*Float32Size),
+		)
+	}
+	if len(indices) > 0 {
+		// The element buffer binding is part of the VAO state
+		gl.GenBuffers(1, &m.eboIndices)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.eboIndices)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*Uint32Size, gl.Ptr(indices), usage)
+	}
+
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	return m
+}
+
+// NewFullscreenQuad creates a quad covering the whole viewport, made of two triangles to draw with gl.TRIANGLES.
+// The vertices are x,y in normalized device coordinates at location 0 and u,v at location 1, with UV 0,0 at the
+// bottom-left corner and 1,1 at the top-right, the same layout VertexShaderFullscreen expects
+func NewFullscreenQuad() *Mesh {
+	vertices := []float32{
+		-1, -1, 0, 0,
+		1, -1, 1, 0,
+		1, 1, 1, 1,
+		-1, -1, 0, 0,
+		1, 1, 1, 1,
+		-1, 1, 0, 1,
+	}
+	return NewMesh(vertices, []VertexAttribute{
+		{Location: 0, Size: 2, Offset: 0, Stride: 4},
+		{Location: 1, Size: 2, Offset: 2, Stride: 4},
+	})
+}
+
+// NewTileMapMesh builds a single mesh for a grid of cols x rows tiles of tileSize, to draw with gl.TRIANGLES.
+// uvRects[row][col] is the pixel rectangle of the tile in an atlas of atlasSize pixels (e.g. one of those returned by
+// TextureAtlas.Build). Empty rectangles, and rows or columns missing from uvRects, leave a hole in the map.
+// Tile 0,0 has its top-left corner at the origin and the rows go along +Y, like the Y-down Camera2D; the vertices are
+// x,y at location 0 and u,v at location 1
+func NewTileMapMesh(cols, rows int, tileSize mgl32.Vec2, uvRects [][]image.Rectangle, atlasSize mgl32.Vec2) *Mesh {
+	vertices := make([]float32, 0, cols*rows*4*4)
+	indices := make([]uint32, 0, cols*rows*6)
+	for row := 0; row < rows && row < len(uvRects); row++ {
+		for col := 0; col < cols && col < len(uvRects[row]); col++ {
+			rect := uvRects[row][col]
+			if rect.Empty() {
+				continue
+			}
+			x0, y0 := float32(col)*tileSize.X(), float32(row)*tileSize.Y()
+			x1, y1 := x0+tileSize.X(), y0+tileSize.Y()
+			u0, v0 := float32(rect.Min.X)/atlasSize.X(), float32(rect.Min.Y)/atlasSize.Y()
+			u1, v1 := float32(rect.Max.X)/atlasSize.X(), float32(rect.Max.Y)/atlasSize.Y()
+			first := uint32(len(vertices) / 4)
+			vertices = append(vertices,
+				x0, y0, u0, v0,
+				x1, y0, u1, v0,
+				x1, y1, u1, v1,
+				x0, y1, u0, v1,
+			)
+			indices = append(indices, first, first+1, first+2, first, first+2, first+3)
+		}
+	}
+	return NewIndexedMesh(vertices, indices, []VertexAttribute{
+		{Location: 0, Size: 2, Offset: 0, Stride: 4},
+		{Location: 1, Size: 2, Offset: 2, Stride: 4},
+	})
+}
+
+var fullscreenQuad *Mesh
+
+// DrawFullscreen runs a post-processing pass: it uses the program, binds the texture to the unit 0 and draws a
+// fullscreen quad over the current viewport. The quad is created the first time and shared by all the calls
+func DrawFullscreen(s *ShaderProgram, tex *Texture) {
+	if fullscreenQuad == nil {
+		fullscreenQuad = NewFullscreenQuad()
+	}
+	s.Use()
+	tex.BindToUnit(0)
+	fullscreenQuad.Draw(gl.TRIANGLES)
+}
+
+// floatsPerVertex returns the number of float32 making up a vertex
+func floatsPerVertex(attributes []VertexAttribute) int {
+	count := 0
+	for _, attribute := range attributes {
+		if attribute.Stride != 0 {
+			return int(attribute.Stride)
+		}
+		count += int(attribute.Size)
+	}
+	if count == 0 {
+		return 1
+	}
+	return count
+}
+
+// Draw draws the mesh with the given primitive mode (e.g. gl.TRIANGLES), using the indices if the mesh has them
+func (m *Mesh) Draw(mode uint32) {
+	gl.BindVertexArray(m.vaoID)
+	if m.indexCount > 0 {
+		gl.DrawElements(mode, m.indexCount, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	} else {
+		gl.DrawArrays(mode, 0, m.vertexCount)
+	}
+	gl.BindVertexArray(0)
+}
+
+// UpdateVertices replaces the vertex data, keeping the attribute layout. The buffer is updated in place when the new
+// data fits, and reallocated when it's larger. The vertex count follows the new data, the indices are untouched
+func (m *Mesh) UpdateVertices(vertices []float32) {
+	if len(vertices) == 0 {
+		m.vertexCount = 0
+		return
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vboVertices)
+	if len(vertices) <= m.vertexCapacity {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*Float32Size, gl.Ptr(vertices))
+	} else {
+		gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*Float32Size, gl.Ptr(vertices), m.usage)
+		m.vertexCapacity = len(vertices)
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	m.vertexCount = int32(len(vertices) / m.vertexSize)
+}
+
+// SetInstanceData uploads per-instance attributes (e.g. the offset and color of every sprite) to a second vertex
+// buffer. The attributes advance once every divisor instances instead of once per vertex, and they use their own
+// layout: Offset and Stride refer to data. Calling it again replaces the previous data, empty data removes the
+// instances and DrawInstanced must then be called with a count of 0
+func (m *Mesh) SetInstanceData(data []float32, attributes []VertexAttribute, divisor uint32) {
+	gl.BindVertexArray(m.vaoID)
+	if m.vboInstances == 0 {
+		gl.GenBuffers(1, &m.vboInstances)
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vboInstances)
+	// Instance data usually changes every frame. Empty data leaves an empty buffer, there are no instances to draw
+	var pointer unsafe.Pointer
+	if len(data) > 0 {
+		pointer = gl.Ptr(data)
+	}
+	gl.BufferData(gl.ARRAY_BUFFER, len(data)*Float32Size, pointer, gl.DYNAMIC_DRAW)
+	for _, attribute := range attributes {
+		gl.EnableVertexAttribArray(attribute.Location)
+		gl.VertexAttribPointer(
+			attribute.Location, attribute.Size, gl.FLOAT, false,
+			attribute.Stride*Float32Size, gl.PtrOffset(attribute.Offset*Float32Size),
+		)
+		gl.VertexAttribDivisor(attribute.Location, divisor)
+	}
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// DrawInstanced draws count instances of the mesh with the given primitive mode, using the indices if the mesh has
+// them. The per-instance attributes come from SetInstanceData
+func (m *Mesh) DrawInstanced(mode uint32, count int32) {
+	gl.BindVertexArray(m.vaoID)
+	if m.indexCount > 0 {
+		gl.DrawElementsInstanced(mode, m.indexCount, gl.UNSIGNED_INT, gl.PtrOffset(0), count)
+	} else {
+		gl.DrawArraysInstanced(mode, 0, m.vertexCount, count)
+	}
+	gl.BindVertexArray(0)
+}
+
+// SetLabel names the vertex array of the mesh in the GPU debuggers. It does nothing without KHR_debug
+func (m *Mesh) SetLabel(name string) {
+	objectLabel(gl.VERTEX_ARRAY, m.vaoID, name)
+}
+
+// VertexCount returns the number of vertices of the mesh
+func (m *Mesh) VertexCount() int32 {
+	return m.vertexCount
+}
+
+// IndexCount returns the number of indices of the mesh, 0 if it's not indexed
+func (m *Mesh) IndexCount() int32 {
+	return m.indexCount
+}
+
+// Delete releases the vertex array and its buffers. It's safe to call it more than once
+func (m *Mesh) Delete() {
+	if m == nil {
+		return
+	}
+	if m.vboVertices != 0 {
+		gl.DeleteBuffers(1, &m.vboVertices)
+		m.vboVertices = 0
+	}
+	if m.eboIndices != 0 {
+		gl.DeleteBuffers(1, &m.eboIndices)
+		m.eboIndices = 0
+	}
+	if m.vboInstances != 0 {
+		gl.DeleteBuffers(1, &m.vboInstances)
+		m.vboInstances = 0
+	}
+	if m.vaoID != 0 {
+		gl.DeleteVertexArrays(1, &m.vaoID)
+		m.vaoID = 0
+	}
+}
@@ -0,0 +1,29 @@
+//go:build gl_context
+// +build gl_context
+
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestEmptyInstanceData(t *testing.T) {
+	withContext(t)
+	framebuffer := renderTarget(t, 4, 4, DepthAttachmentNone)
+	defer framebuffer.Delete()
+	mesh := NewMesh([]float32{0, 0, 1, 0, 0, 1}, []VertexAttribute{{Location: 0, Size: 2, Offset: 0, Stride: 2}})
+	defer mesh.Delete()
+	offsets := []VertexAttribute{{Location: 1, Size: 2, Offset: 0, Stride: 2}}
+
+	mesh.SetInstanceData([]float32{0, 0, 1, 1}, offsets, 1)
+	// Removing the instances doesn't hand an empty slice to gl.Ptr
+	mesh.SetInstanceData(nil, offsets, 1)
+	mesh.SetInstanceData([]float32{}, offsets, 1)
+	mesh.DrawInstanced(gl.TRIANGLES, 0)
+	framebuffer.Unbind()
+	if code := gl.GetError(); code != gl.NO_ERROR {
+		t.Errorf("GL error 0x%X", code)
+	}
+}
@@ -9,6 +9,8 @@ import (
 const (
 	// Float32Size is the size (in bytes) of a float32
 	Float32Size = 4
+	// Uint32Size is the size (in bytes) of a uint32
+	Uint32Size = 4
 )
 
 // ModelMatrix matrix representing the primitive transformation
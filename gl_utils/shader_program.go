@@ -1,8 +1,10 @@
 package gl_utils
 
 import (
+	"errors"
 	"fmt"
 	"github.com/go-gl/mathgl/mgl64"
+	"sort"
 	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -19,6 +21,20 @@ const (
 	FRAGMENT ShaderType = gl.FRAGMENT_SHADER
 )
 
+// String returns the name of the shader stage
+func (t ShaderType) String() string {
+	switch t {
+	case VERTEX:
+		return "vertex"
+	case GEOMETRY:
+		return "geometry"
+	case FRAGMENT:
+		return "fragment"
+	default:
+		return fmt.Sprintf("ShaderType(0x%X)", uint32(t))
+	}
+}
+
 // ShaderProgram a representation of an OpenGL shader program
 type ShaderProgram struct {
 	id       uint32
@@ -56,23 +72,64 @@ func NewShaderProgram(vertSource string, geomSource string, fragSource string) *
 	return &s
 }
 
-// Release releases all the resources associated with this program
-func (s *ShaderProgram) Release() {
-	if s.id == 0 {
-		fmt.Printf("Error: Trying to release a non initialized shader program")
+// NewShaderProgramFromSource compiles and links a program made of a vertex and a fragment shader.
+// Unlike NewShaderProgram it doesn't print anything, the compiler or linker log is returned inside the error
+func NewShaderProgramFromSource(vertSource string, fragSource string) (*ShaderProgram, error) {
+	return NewShaderProgramFromSources(map[ShaderType]string{
+		VERTEX:   vertSource,
+		FRAGMENT: fragSource,
+	})
+}
+
+// NewShaderProgramFromSources compiles and links a program made of any combination of stages, keyed by their type.
+// The error names the stage that failed to compile
+func NewShaderProgramFromSources(sources map[ShaderType]string) (*ShaderProgram, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("failed to create program: no shader sources")
 	}
-	// TODO
-	//var shadersId [8]uint32
-	//shaders_id := gl.GetAttachedShaders(s.id, 8, 8, &shadersId )
-	//for id in  shaders_id:
-	//	gl.DetachShader(self._program_id, shader_id)
-	//	gl.DeleteShader(shader_id)
+	s := &ShaderProgram{}
+	s.id = gl.CreateProgram()
 
-	gl.DeleteProgram(s.id)
+	// Compile the stages in a deterministic order, so that the same error is always reported first
+	stages := make([]ShaderType, 0, len(sources))
+	for shaderType := range sources {
+		stages = append(stages, shaderType)
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i] < stages[j] })
+
+	shaderIDs := make([]uint32, 0, len(stages))
+	var err error
+	for _, shaderType := range stages {
+		var shaderID uint32
+		shaderID, err = compileShader(sources[shaderType], shaderType)
+		if err != nil {
+			break
+		}
+		gl.AttachShader(s.id, shaderID)
+		shaderIDs = append(shaderIDs, shaderID)
+	}
+	if err == nil {
+		err = s.link()
+	}
+
+	// Once linked the program doesn't need the shader objects anymore
+	for _, shaderID := range shaderIDs {
+		gl.DetachShader(s.id, shaderID)
+		gl.DeleteShader(shaderID)
+	}
+	if err != nil {
+		s.Delete()
+		return nil, err
+	}
+	return s, nil
 }
 
-// AttachShader attaches a shader to this program
-func (s *ShaderProgram) AttachShader(source string, shaderType ShaderType) {
+// compileShader compiles a shader stage, returning the compiler log as an error on failure
+func compileShader(source string, shaderType ShaderType) (uint32, error) {
+	// gl.Strs doesn't terminate the string and no length is passed, so the driver reads the source up to a NUL
+	if !strings.HasSuffix(source, "\x00") {
+		source += "\x00"
+	}
 	shaderID := gl.CreateShader(uint32(shaderType))
 	cSource, free := gl.Strs(source)
 	gl.ShaderSource(shaderID, 1, cSource, nil)
@@ -87,14 +144,15 @@ func (s *ShaderProgram) AttachShader(source string, shaderType ShaderType) {
 
 		logStr := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shaderID, logLength, nil, gl.Str(logStr))
+		gl.DeleteShader(shaderID)
 
-		fmt.Printf("Error: failed to compile %v: %v", source, logStr)
+		return 0, fmt.Errorf("failed to compile %v shader: %v", shaderType, strings.TrimRight(logStr, "\x00"))
 	}
-	gl.AttachShader(s.id, shaderID)
+	return shaderID, nil
 }
 
-// Link links together all the shaders into a shader program
-func (s *ShaderProgram) Link() {
+// link links the attached shaders, returning the linker log as an error on failure
+func (s *ShaderProgram) link() error {
 	gl.LinkProgram(s.id)
 	var status int32
 	gl.GetProgramiv(s.id, gl.LINK_STATUS, &status)
@@ -105,8 +163,60 @@ func (s *ShaderProgram) Link() {
 		logStr := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(s.id, logLength, nil, gl.Str(logStr))
 
-		fmt.Printf("Error: failed to link program: %v", logStr)
+		return fmt.Errorf("failed to link program: %v", strings.TrimRight(logStr, "\x00"))
+	}
+	return nil
+}
+
+// Use makes this program part of the current rendering state
+func (s *ShaderProgram) Use() {
+	gl.UseProgram(s.id)
+}
+
+// Delete releases the program. It's safe to call it more than once
+func (s *ShaderProgram) Delete() {
+	if s == nil || s.id == 0 {
+		return
+	}
+	gl.DeleteProgram(s.id)
+	s.id = 0
+}
+
+// Release releases all the resources associated with this program
+func (s *ShaderProgram) Release() {
+	if s.id == 0 {
+		fmt.Printf("Error: Trying to release a non initialized shader program")
+	}
+	// TODO
+	//var shadersId [8]uint32
+	//shaders_id := gl.GetAttachedShaders(s.id, 8, 8, &shadersId )
+	//for id in  shaders_id:
+	//	gl.DetachShader(self._program_id, shader_id)
+	//	gl.DeleteShader(shader_id)
+
+	gl.DeleteProgram(s.id)
+}
+
+// AttachShader attaches a shader to this program
+func (s *ShaderProgram) AttachShader(source string, shaderType ShaderType) {
+	shaderID, err := compileShader(source, shaderType)
+	if err != nil {
+		fmt.Printf("Error: %v\n%v", err, source)
+		return
 	}
+	gl.AttachShader(s.id, shaderID)
+}
+
+// Link links together all the shaders into a shader program
+func (s *ShaderProgram) Link() {
+	if err := s.link(); err != nil {
+		fmt.Printf("Error: %v", err)
+	}
+}
+
+// SetLabel names the program in the GPU debuggers. It does nothing without KHR_debug
+func (s *ShaderProgram) SetLabel(name string) {
+	objectLabel(gl.PROGRAM, s.id, name)
 }
 
 // ID returns the OpenGL ID assigned to this shader program
@@ -130,6 +240,67 @@ func (s *ShaderProgram) GetUniform(name string) int32 {
 	return uniform
 }
 
+// SetUniformMat4 sets a mat4 uniform. It returns false if the program has no active uniform with that name
+// (e.g. because the compiler optimized it away)
+func (s *ShaderProgram) SetUniformMat4(name string, m mgl32.Mat4) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.UniformMatrix4fv(uniform, 1, false, &m[0])
+	return true
+}
+
+// SetUniformVec2 sets a vec2 uniform. It returns false if the program has no active uniform with that name
+func (s *ShaderProgram) SetUniformVec2(name string, v mgl32.Vec2) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.Uniform2fv(uniform, 1, &v[0])
+	return true
+}
+
+// SetUniformVec3 sets a vec3 uniform. It returns false if the program has no active uniform with that name
+func (s *ShaderProgram) SetUniformVec3(name string, v mgl32.Vec3) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.Uniform3fv(uniform, 1, &v[0])
+	return true
+}
+
+// SetUniformVec4 sets a vec4 uniform. It returns false if the program has no active uniform with that name
+func (s *ShaderProgram) SetUniformVec4(name string, v mgl32.Vec4) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.Uniform4fv(uniform, 1, &v[0])
+	return true
+}
+
+// SetUniformInt sets an int (or sampler) uniform. It returns false if the program has no active uniform with that name
+func (s *ShaderProgram) SetUniformInt(name string, v int32) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.Uniform1i(uniform, v)
+	return true
+}
+
+// SetUniformFloat sets a float uniform. It returns false if the program has no active uniform with that name
+func (s *ShaderProgram) SetUniformFloat(name string, v float32) bool {
+	uniform := s.GetUniform(name)
+	if uniform < 0 {
+		return false
+	}
+	gl.Uniform1f(uniform, v)
+	return true
+}
+
 // SetUniform sets the shader's uniforms based on the type of the value passed
 func (s *ShaderProgram) SetUniform(name string, val interface{}) {
 	uniform := s.GetUniform(name)
@@ -183,6 +354,22 @@ const (
         }
         ` + "\x00"
 
+	// VertexShaderFullscreen passes through the vertices of NewFullscreenQuad, already in normalized device
+	// coordinates, for the post-processing passes drawn with DrawFullscreen
+	VertexShaderFullscreen = `
+        #version 410 core
+
+        layout(location=0) in vec2 vertex;
+        layout(location=1) in vec2 uv;
+
+        out vec2 uv_out;
+
+        void main() {
+            gl_Position = vec4(vertex, 0, 1);
+            uv_out = uv;
+        }
+        ` + "\x00"
+
 	// FragmentShaderSolidColor used to have a solid color shape/primitive
 	FragmentShaderSolidColor = `
         #version 410 core
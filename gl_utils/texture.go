@@ -1,106 +1,313 @@
 package gl_utils
 
 import (
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
+	"image/png"
+	"io"
 	"os"
+	"unsafe"
 	// Used only to initialize the JPEG subsystem
 	_ "image/jpeg"
-	// Used only to initialize the PNG subsystem
-	_ "image/png"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 // Texture a representation of an image file in memory
 type Texture struct {
-	id     uint32
-	width  int32
-	height int32
+	id                 uint32
+	target             uint32
+	width              int32
+	height             int32
+	premultipliedAlpha bool
+	// Storage of level 0, needed to reallocate the texture
+	internalFormat int32
+	format         uint32
+	pixelType      uint32
+	// Whether the rows were uploaded bottom to top, with the FlipVertically option
+	flipped bool
+	// Number of layers of a gl.TEXTURE_2D_ARRAY
+	layers int32
+	// Size of the image inside a texture padded to a power of two, 0 when the image fills the texture
+	contentWidth  int32
+	contentHeight int32
+	// Source image kept with the RetainImage option
+	image image.Image
 }
 
+var maxTextureUnits int32
+
+// MaxTextureUnits returns the number of texture units available to the shaders. The value is queried once and cached
+func MaxTextureUnits() int32 {
+	if maxTextureUnits == 0 {
+		gl.GetIntegerv(gl.MAX_COMBINED_TEXTURE_IMAGE_UNITS, &maxTextureUnits)
+	}
+	return maxTextureUnits
+}
+
+var maxTextureSize int32
+
+// MaxTextureSize returns the largest width and height of a texture supported by the context. The value is queried
+// once and cached
+func MaxTextureSize() int32 {
+	if maxTextureSize == 0 {
+		gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &maxTextureSize)
+	}
+	return maxTextureSize
+}
+
+// checkTextureSize returns an error for the sizes that can't be allocated, which would give a black texture
+func checkTextureSize(width, height int32) error {
+	if maxSize := MaxTextureSize(); width > maxSize || height > maxSize {
+		return fmt.Errorf("%dx%d exceeds the maximum texture size %d", width, height, maxSize)
+	}
+	return nil
+}
+
+// TextureOptions configures the sampling parameters of a texture
+type TextureOptions struct {
+	MinFilter int32
+	MagFilter int32
+	// WrapS and WrapT accept gl.CLAMP_TO_EDGE, gl.REPEAT, gl.MIRRORED_REPEAT and gl.CLAMP_TO_BORDER. The border is
+	// transparent black unless changed with SetBorderColor
+	WrapS int32
+	WrapT int32
+	// GenerateMipmaps builds the mipmap chain after the upload and switches to trilinear minification
+	GenerateMipmaps bool
+	// FlipVertically uploads the rows bottom to top, so that the first row of the image ends up at V=1
+	FlipVertically bool
+	// PadToPowerOfTwo rounds the texture size up to the next powers of two, leaving the image at UV 0,0 and the
+	// rest transparent. ContentUV returns the part of the texture covered by the image
+	PadToPowerOfTwo bool
+	// SRGB stores 8-bit color images as GL_SRGB8_ALPHA8, so that the GPU converts the texels to linear when sampling.
+	// Use it for the color textures only, never for data (normal maps, roughness, masks...). The output must be
+	// encoded back with gl.Enable(gl.FRAMEBUFFER_SRGB) or in the shader. Other images return an error
+	SRGB bool
+	// RetainImage keeps a reference to the source image, returned by Texture.Image, for CPU side queries like
+	// per-pixel hit tests. The image stays in memory as long as the texture does, on top of the GPU copy
+	RetainImage bool
+	// MaxSize, when positive, scales down the images wider or taller than MaxSize pixels with ResizeImage, keeping
+	// their aspect ratio. The scaled images are uploaded as 8-bit RGBA
+	MaxSize int
+	// PremultiplyAlpha stores the color components multiplied by alpha, whatever the kind of source image, instead
+	// of the straight alpha used by default. See Texture.PremultipliedAlpha for the blending each one needs
+	PremultiplyAlpha bool
+}
+
+// DefaultTextureOptions returns the options used by NewTextureFromImage: linear filtering and clamped edges
+func DefaultTextureOptions() TextureOptions {
+	return TextureOptions{
+		MinFilter: gl.LINEAR,
+		MagFilter: gl.LINEAR,
+		WrapS:     gl.CLAMP_TO_EDGE,
+		WrapT:     gl.CLAMP_TO_EDGE,
+	}
+}
+
+// ErrUnsupportedImageFormat is returned for the image types that have no matching OpenGL pixel layout
+var ErrUnsupportedImageFormat = errors.New("unsupported image format")
+
 // NewTextureFromFile loads the image from a file into a texture
-func NewTextureFromFile(filePath string) *Texture {
+func NewTextureFromFile(filePath string) (*Texture, error) {
+	return newTextureFromFileWithOptions(filePath, DefaultTextureOptions())
+}
+
+// NewPixelArtTexture loads the image from a file into a texture meant to be magnified without blurring: nearest
+// filtering and clamped edges. There are no mipmaps, their averaged texels don't suit pixel art
+func NewPixelArtTexture(filePath string) (*Texture, error) {
+	return newTextureFromFileWithOptions(filePath, TextureOptions{
+		MinFilter: gl.NEAREST,
+		MagFilter: gl.NEAREST,
+		WrapS:     gl.CLAMP_TO_EDGE,
+		WrapT:     gl.CLAMP_TO_EDGE,
+	})
+}
+
+func newTextureFromFileWithOptions(filePath string, opts TextureOptions) (*Texture, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Printf("Error loading texture. %s\n", err)
-		return nil
+		return nil, fmt.Errorf("loading texture %q: %w", filePath, err)
 	}
 	defer file.Close()
 
-	decodedImage, format, err := image.Decode(file)
+	texture, err := newTextureFromReaderWithOptions(file, opts)
 	if err != nil {
-		fmt.Printf("Error decoding <%s> image: '%s'\n", format, filePath)
-		return nil
+		return nil, fmt.Errorf("loading texture %q: %w", filePath, err)
 	}
-	return NewTextureFromImage(decodedImage)
+	return texture, nil
+}
+
+// NewTextureFromReader decodes an image (PNG or JPEG) from a reader into a texture
+func NewTextureFromReader(r io.Reader) (*Texture, error) {
+	return newTextureFromReaderWithOptions(r, DefaultTextureOptions())
+}
+
+func newTextureFromReaderWithOptions(r io.Reader, opts TextureOptions) (*Texture, error) {
+	decodedImage, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+	return NewTextureFromImageWithOptions(decodedImage, opts)
 }
 
 // NewTextureFromImage uses the data from an Image struct to create a texture
-func NewTextureFromImage(imageData image.Image) *Texture {
+func NewTextureFromImage(imageData image.Image) (*Texture, error) {
+	return NewTextureFromImageWithOptions(imageData, DefaultTextureOptions())
+}
+
+// NewTextureFromImageWithOptions uses the data from an Image struct to create a texture with custom sampling parameters
+func NewTextureFromImageWithOptions(imageData image.Image, opts TextureOptions) (*Texture, error) {
 	texture := &Texture{
+		target: gl.TEXTURE_2D,
 		width:  int32(imageData.Bounds().Dx()),
 		height: int32(imageData.Bounds().Dy()),
 	}
+	if opts.RetainImage {
+		texture.image = imageData
+	}
+	if size := imageData.Bounds().Size(); opts.MaxSize > 0 && (size.X > opts.MaxSize || size.Y > opts.MaxSize) {
+		scale := float64(opts.MaxSize) / float64(maxInt(size.X, size.Y))
+		width := maxInt(int(float64(size.X)*scale+0.5), 1)
+		height := maxInt(int(float64(size.Y)*scale+0.5), 1)
+		imageData = ResizeImage(imageData, width, height)
+		texture.width, texture.height = int32(width), int32(height)
+	}
+	imageData = alphaModeImage(imageData, opts.PremultiplyAlpha)
+	if opts.PadToPowerOfTwo {
+		potWidth, potHeight := NextPowerOfTwo(texture.width), NextPowerOfTwo(texture.height)
+		if potWidth != texture.width || potHeight != texture.height {
+			imageData = paddedImage(imageData, int(potWidth), int(potHeight), opts.FlipVertically)
+			texture.contentWidth, texture.contentHeight = texture.width, texture.height
+			texture.width, texture.height = potWidth, potHeight
+		}
+	}
+	if err := checkTextureSize(texture.width, texture.height); err != nil {
+		return nil, fmt.Errorf("creating texture: %w", err)
+	}
+
+	internalFormat, format, pixelType, err := GLFormatForImage(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("creating texture: %w", err)
+	}
+	if opts.SRGB {
+		if internalFormat != gl.RGBA8 {
+			return nil, fmt.Errorf("creating texture: sRGB needs an 8-bit color image, got %T", imageData)
+		}
+		internalFormat = gl.SRGB8_ALPHA8
+	}
+	texture.premultipliedAlpha = opts.PremultiplyAlpha
+	texture.flipped = opts.FlipVertically
+	texture.internalFormat = internalFormat
+	texture.format = format
+	texture.pixelType = pixelType
+	pixels, _, _, err := texture.storagePixels(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("creating texture: %w", err)
+	}
+
 	gl.GenTextures(1, &texture.id)
 	gl.ActiveTexture(gl.TEXTURE0)
 	gl.BindTexture(gl.TEXTURE_2D, texture.id)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, opts.MinFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, opts.MagFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, opts.WrapS)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, opts.WrapT)
+	// Single channel rows are not 4-byte aligned unless the width is a multiple of 4
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(
+		gl.TEXTURE_2D, 0, texture.internalFormat, texture.width, texture.height,
+		0, texture.format, texture.pixelType, pixels,
+	)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
 
-	switch imageData.(type) {
+	if opts.GenerateMipmaps {
+		texture.GenerateMipmaps()
+	}
+	if err := debugCheckGLError("creating texture"); err != nil {
+		texture.Delete()
+		return nil, err
+	}
+
+	return texture, nil
+}
+
+// GLFormatForImage returns the OpenGL formats matching the memory layout of an image, to upload it without
+// conversions: image.Gray --> R8, image.Gray16 --> R16, image.RGBA and image.NRGBA --> RGBA8, image.RGBA64 and
+// image.NRGBA64 --> RGBA16. The other image types (e.g. image.YCbCr, image.Paletted) return ErrUnsupportedImageFormat,
+// NewTextureFromImage converts them to RGBA8
+func GLFormatForImage(img image.Image) (internalFormat int32, format uint32, pixelType uint32, err error) {
+	switch img.(type) {
+	case *image.Gray:
+		return gl.R8, gl.RED, gl.UNSIGNED_BYTE, nil
 	case *image.Gray16:
-		// 16-bit monochrome image --> Gray
-		grayImage := image.NewGray(imageData.Bounds())
-		if grayImage.Stride != grayImage.Rect.Size().X*1 {
-			fmt.Println("Error creating texture: unsupported stride")
-			return nil
-		}
-		draw.Draw(grayImage, grayImage.Bounds(), imageData, image.Point{0, 0}, draw.Src)
-		gl.TexImage2D(
-			gl.TEXTURE_2D, 0, gl.RED, texture.width, texture.height,
-			0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(grayImage.Pix),
-		)
-	case *image.NRGBA:
-		// non-alpha-premultiplied 32-bit color image --> RGBA
-		pixelData := imageData.(*image.NRGBA).Pix
-		gl.TexImage2D(
-			gl.TEXTURE_2D, 0, gl.RGBA, texture.width, texture.height,
-			0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixelData),
-		)
-	default:
-		// All the other formats -->  RGBA
-		rgba := image.NewRGBA(imageData.Bounds())
-		if rgba.Stride != rgba.Rect.Size().X*4 {
-			fmt.Println("Error creating texture: unsupported stride")
-			return nil
-		}
-		draw.Draw(rgba, rgba.Bounds(), imageData, image.Point{0, 0}, draw.Src)
-		gl.TexImage2D(
-			gl.TEXTURE_2D, 0, gl.RGBA, texture.width, texture.height,
-			0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix),
-		)
+		return gl.R16, gl.RED, gl.UNSIGNED_SHORT, nil
+	case *image.RGBA, *image.NRGBA:
+		return gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, nil
+	case *image.RGBA64, *image.NRGBA64:
+		return gl.RGBA16, gl.RGBA, gl.UNSIGNED_SHORT, nil
 	}
+	return 0, 0, 0, fmt.Errorf("%w %T", ErrUnsupportedImageFormat, img)
+}
 
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+// clientFormat returns the client format and type GLFormatForImage pairs with an internal format: gl.RED with R8 and
+// R16, 16-bit components with R16 and RGBA16, 8-bit RGBA for the others
+func clientFormat(internalFormat int32) (format uint32, pixelType uint32) {
+	switch internalFormat {
+	case gl.R8:
+		return gl.RED, gl.UNSIGNED_BYTE
+	case gl.R16:
+		return gl.RED, gl.UNSIGNED_SHORT
+	case gl.RGBA16:
+		return gl.RGBA, gl.UNSIGNED_SHORT
+	}
+	return gl.RGBA, gl.UNSIGNED_BYTE
+}
 
-	return texture
+// NewTextureFromColor creates a texture filled with a solid color. The texels are stored alpha-premultiplied, like
+// image.RGBA, so that a ToImage readback returns an *image.RGBA of the same color
+func NewTextureFromColor(width int, height int, c color.Color) (*Texture, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("creating texture: invalid size %dx%d", width, height)
+	}
+	opts := DefaultTextureOptions()
+	opts.PremultiplyAlpha = true
+	return NewTextureFromImageWithOptions(solidColorImage(width, height, c), opts)
 }
 
-// NewEmptyTexture creates an empty texture with a specified size
-func NewEmptyTexture(width int, height int, pixelFormat int32) (*Texture, error) {
-	bounds := image.Rectangle{
-		Min: image.Point{X: 0, Y: 0},
-		Max: image.Point{X: width, Y: height},
+// solidColorImage returns an image filled with a color
+func solidColorImage(width int, height int, c color.Color) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	texel := color.RGBAModel.Convert(c).(color.RGBA)
+	copy(rgba.Pix, []uint8{texel.R, texel.G, texel.B, texel.A})
+	// Fill the buffer doubling the initialized part at every step
+	for filled := 4; filled < len(rgba.Pix); filled *= 2 {
+		copy(rgba.Pix[filled:], rgba.Pix[:filled])
 	}
-	imageData := image.NewRGBA(bounds)
+	return rgba
+}
 
+// NewEmptyTexture allocates an uninitialized texture with a specified size. internalFormat is the format used to store
+// the texels on the GPU, format and pixelType describe the client side layout (e.g. gl.DEPTH_COMPONENT24,
+// gl.DEPTH_COMPONENT, gl.FLOAT for a depth texture)
+func NewEmptyTexture(width int, height int, internalFormat int32, format uint32, pixelType uint32) (*Texture, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("creating texture: invalid size %dx%d", width, height)
+	}
+	if err := checkTextureSize(int32(width), int32(height)); err != nil {
+		return nil, fmt.Errorf("creating texture: %w", err)
+	}
 	texture := &Texture{
-		width:  int32(imageData.Bounds().Dx()),
-		height: int32(imageData.Bounds().Dy()),
+		target:         gl.TEXTURE_2D,
+		width:          int32(width),
+		height:         int32(height),
+		internalFormat: internalFormat,
+		format:         format,
+		pixelType:      pixelType,
 	}
 	gl.GenTextures(1, &texture.id)
 	gl.ActiveTexture(gl.TEXTURE0)
@@ -110,20 +317,800 @@ func NewEmptyTexture(width int, height int, pixelFormat int32) (*Texture, error)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 	gl.TexImage2D(
-		gl.TEXTURE_2D, 0, pixelFormat, texture.width, texture.height,
-		0, uint32(pixelFormat), gl.UNSIGNED_BYTE, gl.Ptr(imageData.Pix),
+		gl.TEXTURE_2D, 0, internalFormat, texture.width, texture.height,
+		0, format, pixelType, nil,
 	)
 	gl.BindTexture(gl.TEXTURE_2D, 0)
+	if err := debugCheckGLError("creating texture"); err != nil {
+		texture.Delete()
+		return nil, err
+	}
+
+	return texture, nil
+}
+
+// NewFloatTexture creates a 32-bit floating point texture with 1 (R), 2 (RG), 3 (RGB) or 4 (RGBA) components.
+// data holds width*height*components values, or is nil to leave the texture uninitialized (e.g. for render targets)
+func NewFloatTexture(width, height int32, components int, data []float32) (*Texture, error) {
+	return newFloatTexture(width, height, components, data, false)
+}
+
+// NewHalfFloatTexture is like NewFloatTexture, with the texels stored as 16-bit floats (GL_RGBA16F and so on): half
+// the memory and bandwidth, enough precision for most HDR color buffers. data is converted by OpenGL at the upload
+func NewHalfFloatTexture(width, height int32, components int, data []float32) (*Texture, error) {
+	return newFloatTexture(width, height, components, data, true)
+}
+
+func newFloatTexture(width, height int32, components int, data []float32, half bool) (*Texture, error) {
+	var internalFormat int32
+	var format uint32
+	switch components {
+	case 1:
+		internalFormat, format = gl.R32F, gl.RED
+	case 2:
+		internalFormat, format = gl.RG32F, gl.RG
+	case 3:
+		internalFormat, format = gl.RGB32F, gl.RGB
+	case 4:
+		internalFormat, format = gl.RGBA32F, gl.RGBA
+	default:
+		return nil, fmt.Errorf("creating float texture: unsupported number of components %d", components)
+	}
+	if half {
+		internalFormat = [...]int32{gl.R16F, gl.RG16F, gl.RGB16F, gl.RGBA16F}[components-1]
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("creating float texture: invalid size %dx%d", width, height)
+	}
+	if err := checkTextureSize(width, height); err != nil {
+		return nil, fmt.Errorf("creating float texture: %w", err)
+	}
+	if data != nil && len(data) != int(width)*int(height)*components {
+		return nil, fmt.Errorf(
+			"creating float texture: got %d values, expected %d", len(data), int(width)*int(height)*components,
+		)
+	}
+
+	var pixels unsafe.Pointer
+	if len(data) > 0 {
+		pixels = gl.Ptr(data)
+	}
+	texture := &Texture{
+		target:         gl.TEXTURE_2D,
+		width:          width,
+		height:         height,
+		internalFormat: internalFormat,
+		format:         format,
+		pixelType:      gl.FLOAT,
+	}
+	gl.GenTextures(1, &texture.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, format, gl.FLOAT, pixels)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	if err := debugCheckGLError("creating float texture"); err != nil {
+		texture.Delete()
+		return nil, err
+	}
+
+	return texture, nil
+}
+
+// NewDepthTexture allocates a 24-bit depth texture for shadow mapping. It uses nearest filtering, comparison mode
+// enabled (for sampler2DShadow) and a white border, so everything outside the light frustum is lit.
+// Use SetCompareMode(false) to sample the raw depth values instead
+func NewDepthTexture(width, height int32) (*Texture, error) {
+	texture, err := NewEmptyTexture(int(width), int(height), gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT)
+	if err != nil {
+		return nil, fmt.Errorf("creating depth texture: %w", err)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_MODE, gl.COMPARE_REF_TO_TEXTURE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_COMPARE_FUNC, gl.LEQUAL)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	texture.SetBorderColor(mgl32.Vec4{1, 1, 1, 1})
 
 	return texture, nil
 }
 
+// NewEmptyTextureWithFormat creates an empty texture using pixelFormat both as internal and client format, with
+// unsigned byte components. Kept for the callers of the old NewEmptyTexture signature
+func NewEmptyTextureWithFormat(width int, height int, pixelFormat int32) (*Texture, error) {
+	return NewEmptyTexture(width, height, pixelFormat, uint32(pixelFormat), gl.UNSIGNED_BYTE)
+}
+
+// Bind binds the texture to the texture unit 0. It does nothing if the texture has been deleted
 func (t *Texture) Bind() {
-	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	t.BindToUnit(0)
+}
+
+// BindToUnit activates the texture unit and binds the texture to it. The unit must be lower than MaxTextureUnits(),
+// checking it is the caller's responsibility. It does nothing if the texture has been deleted
+func (t *Texture) BindToUnit(unit uint32) {
+	if t.id == 0 {
+		return
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(t.target, t.id)
 }
 
 func (t *Texture) Unbind() {
+	gl.BindTexture(t.target, 0)
+}
+
+// BindTextures binds each texture to the unit matching its position: the first to unit 0, the second to unit 1 and
+// so on. nil entries are skipped, leaving their unit untouched. The active unit is left at the last bound one
+func BindTextures(textures ...*Texture) {
+	for unit, texture := range textures {
+		if texture != nil {
+			texture.BindToUnit(uint32(unit))
+		}
+	}
+}
+
+// UnbindTextures undoes BindTextures with the same arguments: it unbinds each texture from the unit matching its
+// position, using the target of the texture, and activates the unit 0 again. nil entries are skipped
+func UnbindTextures(textures ...*Texture) {
+	for unit := len(textures) - 1; unit >= 0; unit-- {
+		if textures[unit] != nil {
+			gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+			gl.BindTexture(textures[unit].target, 0)
+		}
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+}
+
+// Resize reallocates the storage of the texture with a new size, keeping its format. The old content is discarded
+func (t *Texture) Resize(width, height int32) error {
+	if t.id == 0 {
+		return errors.New("resizing texture: the texture has been deleted")
+	}
+	if t.target != gl.TEXTURE_2D || t.format == 0 {
+		return errors.New("resizing texture: only uncompressed 2D textures can be resized")
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("resizing texture: invalid size %dx%d", width, height)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, t.internalFormat, width, height, 0, t.format, t.pixelType, nil)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	t.width = width
+	t.height = height
+	t.contentWidth, t.contentHeight = 0, 0
+	return nil
+}
+
+// Clone creates a new 2D texture with the same size, format and sampling parameters, and copies the level 0 into it
+// (e.g. to keep the previous frame of a render target). It uses glCopyImageSubData when the context supports it
+// (GL 4.3 or GL_ARB_copy_image), otherwise it blits between two temporary framebuffers. The mipmaps are rebuilt if
+// the min filter uses them. Compressed textures and the other targets are not supported
+func (t *Texture) Clone() (*Texture, error) {
+	if t.id == 0 {
+		return nil, errors.New("cloning texture: the texture has been deleted")
+	}
+	if t.target != gl.TEXTURE_2D || t.format == 0 {
+		return nil, errors.New("cloning texture: only uncompressed 2D textures can be cloned")
+	}
+	clone, err := NewEmptyTexture(int(t.width), int(t.height), t.internalFormat, t.format, t.pixelType)
+	if err != nil {
+		return nil, fmt.Errorf("cloning texture: %w", err)
+	}
+	clone.premultipliedAlpha = t.premultipliedAlpha
+	clone.contentWidth, clone.contentHeight = t.contentWidth, t.contentHeight
+
+	if glVersionAtLeast(4, 3) || HasExtension("GL_ARB_copy_image") {
+		gl.CopyImageSubData(
+			t.id, gl.TEXTURE_2D, 0, 0, 0, 0,
+			clone.id, gl.TEXTURE_2D, 0, 0, 0, 0,
+			t.width, t.height, 1,
+		)
+	} else if err := blitTexture(t, clone); err != nil {
+		clone.Delete()
+		return nil, fmt.Errorf("cloning texture: %w", err)
+	}
+
+	var minFilter int32
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.GetTexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, &minFilter)
+	parameters := []uint32{
+		gl.TEXTURE_MAG_FILTER, gl.TEXTURE_WRAP_S, gl.TEXTURE_WRAP_T, gl.TEXTURE_COMPARE_MODE, gl.TEXTURE_COMPARE_FUNC,
+	}
+	values := make([]int32, len(parameters))
+	for i, parameter := range parameters {
+		gl.GetTexParameteriv(gl.TEXTURE_2D, parameter, &values[i])
+	}
+	var borderColor mgl32.Vec4
+	gl.GetTexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, &borderColor[0])
+
+	gl.BindTexture(gl.TEXTURE_2D, clone.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	for i, parameter := range parameters {
+		gl.TexParameteri(gl.TEXTURE_2D, parameter, values[i])
+	}
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, &borderColor[0])
+	if minFilter != gl.NEAREST && minFilter != gl.LINEAR {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
 	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return clone, nil
+}
+
+// blitTexture copies the level 0 of a 2D texture into another one of the same size, attaching them to two temporary
+// framebuffers. The framebuffer bindings are reset to the default framebuffer
+func blitTexture(src, dst *Texture) error {
+	attachment, mask := uint32(gl.COLOR_ATTACHMENT0), uint32(gl.COLOR_BUFFER_BIT)
+	if src.format == gl.DEPTH_COMPONENT {
+		attachment, mask = gl.DEPTH_ATTACHMENT, gl.DEPTH_BUFFER_BIT
+	}
+	var framebuffers [2]uint32
+	gl.GenFramebuffers(2, &framebuffers[0])
+	defer gl.DeleteFramebuffers(2, &framebuffers[0])
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, framebuffers[0])
+	gl.FramebufferTexture2D(gl.READ_FRAMEBUFFER, attachment, gl.TEXTURE_2D, src.id, 0)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, framebuffers[1])
+	gl.FramebufferTexture2D(gl.DRAW_FRAMEBUFFER, attachment, gl.TEXTURE_2D, dst.id, 0)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if mask == gl.DEPTH_BUFFER_BIT {
+		// Depth-only framebuffers have no color buffer to read from or draw to
+		gl.ReadBuffer(gl.NONE)
+		gl.DrawBuffer(gl.NONE)
+	}
+
+	if status := gl.CheckFramebufferStatus(gl.READ_FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("the texture can't be read by a framebuffer, status 0x%X", status)
+	}
+	if status := gl.CheckFramebufferStatus(gl.DRAW_FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("the texture can't be drawn by a framebuffer, status 0x%X", status)
+	}
+	gl.BlitFramebuffer(0, 0, src.width, src.height, 0, 0, dst.width, dst.height, mask, gl.NEAREST)
+	return nil
+}
+
+var maxAnisotropy float32 = -1
+
+// MaxAnisotropy returns the highest anisotropic filtering level supported, or 0 when the
+// GL_EXT_texture_filter_anisotropic extension is not available. The value is queried once and cached
+func MaxAnisotropy() float32 {
+	if maxAnisotropy < 0 {
+		maxAnisotropy = 0
+		if HasExtension("GL_EXT_texture_filter_anisotropic") || HasExtension("GL_ARB_texture_filter_anisotropic") {
+			gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &maxAnisotropy)
+		}
+	}
+	return maxAnisotropy
+}
+
+// SetAnisotropy sets the anisotropic filtering level, clamped to [1, MaxAnisotropy()].
+// It returns false, without touching the texture, when anisotropic filtering is not supported
+func (t *Texture) SetAnisotropy(level float32) bool {
+	maxLevel := MaxAnisotropy()
+	if maxLevel == 0 || t.id == 0 {
+		return false
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameterf(t.target, gl.TEXTURE_MAX_ANISOTROPY, mgl32.Clamp(level, 1, maxLevel))
+	gl.BindTexture(t.target, 0)
+	return true
+}
+
+// SetCompareMode enables or disables the depth comparison of a depth texture. When enabled the texture must be
+// sampled with a shadow sampler, when disabled it returns the stored depth
+func (t *Texture) SetCompareMode(enabled bool) {
+	if t.id == 0 {
+		return
+	}
+	var mode int32 = gl.NONE
+	if enabled {
+		mode = gl.COMPARE_REF_TO_TEXTURE
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameteri(t.target, gl.TEXTURE_COMPARE_MODE, mode)
+	gl.BindTexture(t.target, 0)
+}
+
+// SetParameter sets an integer parameter of the texture that has no dedicated method (e.g. gl.TEXTURE_MAX_LEVEL).
+// It must be called on a valid texture: on a deleted one it does nothing
+func (t *Texture) SetParameter(name uint32, value int32) {
+	if t.id == 0 {
+		return
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameteri(t.target, name, value)
+	gl.BindTexture(t.target, 0)
+}
+
+// SetParameterf sets a float parameter of the texture (e.g. gl.TEXTURE_LOD_BIAS).
+// It must be called on a valid texture: on a deleted one it does nothing
+func (t *Texture) SetParameterf(name uint32, value float32) {
+	if t.id == 0 {
+		return
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameterf(t.target, name, value)
+	gl.BindTexture(t.target, 0)
+}
+
+// SetSwizzle chooses the source of each component returned by the shaders when sampling the texture: gl.RED,
+// gl.GREEN, gl.BLUE, gl.ALPHA, gl.ZERO or gl.ONE
+func (t *Texture) SetSwizzle(r, g, b, a int32) {
+	if t.id == 0 {
+		return
+	}
+	swizzle := [4]int32{r, g, b, a}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameteriv(t.target, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+	gl.BindTexture(t.target, 0)
+}
+
+// SwizzleGrayToRGBA makes a single channel texture (e.g. from an image.Gray) sample as an opaque gray (r,r,r,1),
+// instead of the default red (r,0,0,1)
+func (t *Texture) SwizzleGrayToRGBA() {
+	t.SetSwizzle(gl.RED, gl.RED, gl.RED, gl.ONE)
+}
+
+// SwizzleRedToAlpha makes a single channel texture sample as white with the channel as alpha (1,1,1,r), e.g. for
+// glyphs and masks
+func (t *Texture) SwizzleRedToAlpha() {
+	t.SetSwizzle(gl.ONE, gl.ONE, gl.ONE, gl.RED)
+}
+
+// SetBorderColor sets the color sampled outside of the texture. It's used only by the axes whose wrap mode is
+// gl.CLAMP_TO_BORDER
+func (t *Texture) SetBorderColor(c mgl32.Vec4) {
+	if t.id == 0 {
+		return
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameterfv(t.target, gl.TEXTURE_BORDER_COLOR, &c[0])
+	gl.BindTexture(t.target, 0)
+}
+
+// Clear fills the level 0 of a 2D texture with a color, or a depth texture with c[0]. It uses glClearTexImage when
+// the context supports it (GL 4.4 or GL_ARB_clear_texture), otherwise it uploads a width*height buffer of the
+// color, which allocates and transfers 16 bytes per texel at every call
+func (t *Texture) Clear(c mgl32.Vec4) error {
+	if t.id == 0 {
+		return errors.New("clearing texture: the texture has been deleted")
+	}
+	if t.target != gl.TEXTURE_2D || t.format == 0 {
+		return errors.New("clearing texture: only uncompressed 2D textures can be cleared")
+	}
+	format := uint32(gl.RGBA)
+	if t.format == gl.DEPTH_COMPONENT {
+		format = gl.DEPTH_COMPONENT
+	}
+	if glVersionAtLeast(4, 4) || HasExtension("GL_ARB_clear_texture") {
+		gl.ClearTexImage(t.id, 0, format, gl.FLOAT, gl.Ptr(&c[0]))
+		return nil
+	}
+
+	components := 4
+	if format == gl.DEPTH_COMPONENT {
+		components = 1
+	}
+	texels := make([]float32, int(t.width)*int(t.height)*components)
+	copy(texels, c[:components])
+	// Fill the buffer doubling the initialized part at every step
+	for filled := components; filled < len(texels); filled *= 2 {
+		copy(texels[filled:], texels[:filled])
+	}
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, t.width, t.height, format, gl.FLOAT, gl.Ptr(texels))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return nil
+}
+
+// SubImage replaces the region of the texture starting at x,y with the content of the image, converted to the
+// storage of the texture like NewTextureFromImage does. For the textures created with FlipVertically y is counted
+// from the top, like in the source image, and the rows are flipped the same way. Only 2D textures are supported
+func (t *Texture) SubImage(x, y int32, img image.Image) error {
+	if t.id == 0 {
+		return errors.New("updating texture: the texture has been deleted")
+	}
+	if t.target != gl.TEXTURE_2D || t.format == 0 {
+		return errors.New("updating texture: only uncompressed 2D textures are supported, see SetLayer for the arrays")
+	}
+	width := int32(img.Bounds().Dx())
+	height := int32(img.Bounds().Dy())
+	if x < 0 || y < 0 || x+width > t.width || y+height > t.height {
+		return fmt.Errorf(
+			"updating texture: region %dx%d at %d,%d is outside the texture bounds %dx%d",
+			width, height, x, y, t.width, t.height,
+		)
+	}
+
+	pixels, format, pixelType, err := t.storagePixels(img)
+	if err != nil {
+		return fmt.Errorf("updating texture: %w", err)
+	}
+	if t.flipped {
+		y = t.height - y - height
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, x, y, width, height, format, pixelType, pixels)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return debugCheckGLError("updating texture")
+}
+
+// ToImage downloads the content of the texture. OpenGL stores the rows bottom to top, pass flipVertically to get
+// the first row of the image at the top. The image is an *image.RGBA for premultiplied textures and an *image.NRGBA
+// for the straight alpha ones, so that its colors are valid for the image package (e.g. png.Encode)
+func (t *Texture) ToImage(flipVertically bool) (image.Image, error) {
+	if t.id == 0 {
+		return nil, errors.New("reading texture: the texture has been deleted")
+	}
+	bounds := image.Rect(0, 0, int(t.width), int(t.height))
+	pix := make([]uint8, int(t.width)*int(t.height)*4)
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	stride := int(t.width) * 4
+	if flipVertically {
+		flipRows(pix, stride)
+	}
+	if t.premultipliedAlpha {
+		return &image.RGBA{Pix: pix, Stride: stride, Rect: bounds}, nil
+	}
+	return &image.NRGBA{Pix: pix, Stride: stride, Rect: bounds}, nil
+}
+
+// ToImage16 downloads the content of the texture with 16 bits per channel, to read back RGBA16 and R16 textures
+// without losing precision. Like ToImage, pass flipVertically to get the first row of the image at the top, and
+// the image is an *image.RGBA64 or an *image.NRGBA64 depending on the alpha of the texture
+func (t *Texture) ToImage16(flipVertically bool) (image.Image, error) {
+	if t.id == 0 {
+		return nil, errors.New("reading texture: the texture has been deleted")
+	}
+	samples := make([]uint16, int(t.width)*int(t.height)*4)
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RGBA, gl.UNSIGNED_SHORT, gl.Ptr(samples))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	bounds := image.Rect(0, 0, int(t.width), int(t.height))
+	pix := bigEndianUint16(samples)
+	stride := int(t.width) * 8
+	if flipVertically {
+		flipRows(pix, stride)
+	}
+	if t.premultipliedAlpha {
+		return &image.RGBA64{Pix: pix, Stride: stride, Rect: bounds}, nil
+	}
+	return &image.NRGBA64{Pix: pix, Stride: stride, Rect: bounds}, nil
+}
+
+// SaveToPNG writes the content of the texture to a PNG file. Pass flipVertically to store the rows top to bottom,
+// which is what image viewers expect unless the texture has been rendered already flipped
+func (t *Texture) SaveToPNG(path string, flipVertically bool) (err error) {
+	img, err := t.ToImage(flipVertically)
+	if err != nil {
+		return fmt.Errorf("saving texture %q: %w", path, err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("saving texture %q: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("saving texture %q: %w", path, closeErr)
+		}
+	}()
+
+	if err = png.Encode(file, img); err != nil {
+		return fmt.Errorf("saving texture %q: %w", path, err)
+	}
+	return nil
+}
+
+// flipRows reverses the order of the rows of a tightly packed pixel buffer
+func flipRows(pix []uint8, stride int) {
+	row := make([]uint8, stride)
+	for top, bottom := 0, len(pix)-stride; top < bottom; top, bottom = top+stride, bottom-stride {
+		copy(row, pix[top:top+stride])
+		copy(pix[top:top+stride], pix[bottom:bottom+stride])
+		copy(pix[bottom:bottom+stride], row)
+	}
+}
+
+// flippedRows returns a copy of a tightly packed pixel buffer with the rows in reverse order, the source is untouched
+func flippedRows(pix []uint8, stride int) []uint8 {
+	flipped := make([]uint8, len(pix))
+	for top, bottom := 0, len(pix)-stride; bottom >= 0; top, bottom = top+stride, bottom-stride {
+		copy(flipped[top:top+stride], pix[bottom:bottom+stride])
+	}
+	return flipped
+}
+
+// rgbaPixelData returns the image pixels as tightly packed RGBA bytes, premultiplied by alpha or not. RGBA and NRGBA
+// images already of the requested kind are used without copying
+func rgbaPixelData(img image.Image, premultiplied bool) []uint8 {
+	switch img.(type) {
+	case *image.Gray, *image.Gray16, *image.RGBA64, *image.NRGBA64:
+		// Not 8-bit RGBA, alphaModeImage would keep them as they are
+		var converted draw.Image = image.NewNRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+		if premultiplied {
+			converted = image.NewRGBA(converted.Bounds())
+		}
+		draw.Draw(converted, converted.Bounds(), img, img.Bounds().Min, draw.Src)
+		img = converted
+	}
+	return imagePix(alphaModeImage(img, premultiplied))
+}
+
+// storagePixels converts an image to the client layout matching the texture storage and returns the pixels to upload
+// with their format and type: image.Gray for R8 and image.Gray16 for R16 textures, 8 or 16-bit RGBA premultiplied or
+// not like the texture for the color ones (8-bit RGB and RG textures included, OpenGL drops the extra components).
+// The rows are flipped when the texture was created with FlipVertically. The other storages (float, depth,
+// compressed) can't be updated from an image
+func (t *Texture) storagePixels(img image.Image) (pixels unsafe.Pointer, format uint32, pixelType uint32, err error) {
+	bounds := image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())
+	var pixelData []uint8
+	format, pixelType = t.format, t.pixelType
+	switch {
+	case t.format == gl.RED && t.pixelType == gl.UNSIGNED_BYTE:
+		if _, ok := img.(*image.Gray); !ok {
+			img = drawnImage(image.NewGray(bounds), img)
+		}
+		pixelData = imagePix(img)
+	case t.format == gl.RED && t.pixelType == gl.UNSIGNED_SHORT:
+		if _, ok := img.(*image.Gray16); !ok {
+			img = drawnImage(image.NewGray16(bounds), img)
+		}
+		pixelData = imagePix(img)
+	case (t.format == gl.RGBA || t.format == gl.BGRA || t.format == gl.RGB || t.format == gl.RG) &&
+		t.pixelType == gl.UNSIGNED_BYTE:
+		pixelData = rgbaPixelData(img, t.premultipliedAlpha)
+		format = gl.RGBA
+	case t.format == gl.RGBA && t.pixelType == gl.UNSIGNED_SHORT:
+		switch img.(type) {
+		case *image.RGBA64, *image.NRGBA64:
+		default:
+			img = drawnImage(image.NewNRGBA64(bounds), img)
+		}
+		pixelData = imagePix(alphaModeImage(img, t.premultipliedAlpha))
+	default:
+		return nil, 0, 0, fmt.Errorf("the texture format 0x%X can't be updated from an image", t.format)
+	}
+	if len(pixelData) == 0 {
+		return nil, format, pixelType, nil
+	}
+
+	if t.flipped {
+		pixelData = flippedRows(pixelData, len(pixelData)/bounds.Dy())
+	}
+	if pixelType == gl.UNSIGNED_SHORT {
+		return gl.Ptr(nativeUint16(pixelData)), format, pixelType, nil
+	}
+	return gl.Ptr(pixelData), format, pixelType, nil
+}
+
+// drawnImage draws img into dst, converting its colors to the model of dst, and returns dst
+func drawnImage(dst draw.Image, img image.Image) draw.Image {
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	return dst
+}
+
+// alphaModeImage returns the image as one of the types supported by GLFormatForImage, converting it when its colors
+// are not premultiplied by alpha as requested. Gray images have no alpha and are returned as they are, and so are
+// opaque ones, whose premultiplied and straight colors are the same
+func alphaModeImage(img image.Image, premultiplied bool) image.Image {
+	bounds := image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())
+	var converted draw.Image
+	switch src := img.(type) {
+	case *image.Gray, *image.Gray16:
+		return img
+	case *image.RGBA:
+		if premultiplied || src.Opaque() {
+			return img
+		}
+		converted = image.NewNRGBA(bounds)
+	case *image.NRGBA:
+		if !premultiplied || src.Opaque() {
+			return img
+		}
+		converted = image.NewRGBA(bounds)
+	case *image.RGBA64:
+		if premultiplied || src.Opaque() {
+			return img
+		}
+		converted = image.NewNRGBA64(bounds)
+	case *image.NRGBA64:
+		if !premultiplied || src.Opaque() {
+			return img
+		}
+		converted = image.NewRGBA64(bounds)
+	case *image.YCbCr:
+		// OpenGL can't sample YCbCr, so one conversion is unavoidable. JPEG images are opaque and the result goes
+		// through either alpha mode as it is, converting into an image.RGBA lets draw.Draw use its dedicated YCbCr loop
+		// (see BenchmarkNewTextureFromYCbCr)
+		converted = image.NewRGBA(bounds)
+	default:
+		if premultiplied {
+			converted = image.NewRGBA(bounds)
+		} else {
+			converted = image.NewNRGBA(bounds)
+		}
+	}
+	draw.Draw(converted, bounds, img, img.Bounds().Min, draw.Src)
+	return converted
+}
+
+// paddedImage copies an image into a larger one of the same type, if possible, with transparent padding. The image is
+// placed at the top-left corner, or at the bottom-left when the rows are going to be flipped, so that it always ends
+// up at UV 0,0
+func paddedImage(img image.Image, width, height int, flipVertically bool) draw.Image {
+	bounds := image.Rect(0, 0, width, height)
+	var padded draw.Image
+	switch img.(type) {
+	case *image.Gray:
+		padded = image.NewGray(bounds)
+	case *image.Gray16:
+		padded = image.NewGray16(bounds)
+	case *image.NRGBA:
+		padded = image.NewNRGBA(bounds)
+	case *image.RGBA64:
+		padded = image.NewRGBA64(bounds)
+	case *image.NRGBA64:
+		padded = image.NewNRGBA64(bounds)
+	default:
+		padded = image.NewRGBA(bounds)
+	}
+	size := img.Bounds().Size()
+	target := image.Rect(0, 0, size.X, size.Y)
+	if flipVertically {
+		target = target.Add(image.Pt(0, height-size.Y))
+	}
+	draw.Draw(padded, target, img, img.Bounds().Min, draw.Src)
+	return padded
+}
+
+// imagePix returns the pixels of one of the image types supported by GLFormatForImage, with the rows tightly packed
+func imagePix(img image.Image) []uint8 {
+	switch img := img.(type) {
+	case *image.Gray:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*1, img.Rect.Dy())
+	case *image.Gray16:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*2, img.Rect.Dy())
+	case *image.RGBA:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*4, img.Rect.Dy())
+	case *image.NRGBA:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*4, img.Rect.Dy())
+	case *image.RGBA64:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*8, img.Rect.Dy())
+	case *image.NRGBA64:
+		return packedRows(img.Pix, img.Stride, img.Rect.Dx()*8, img.Rect.Dy())
+	}
+	return nil
+}
+
+// packedRows returns the pixel buffer without the padding at the end of the rows. It's not copied when there's none
+func packedRows(pix []uint8, stride, rowSize, rows int) []uint8 {
+	if stride == rowSize {
+		return pix[:rowSize*rows]
+	}
+	packed := make([]uint8, rowSize*rows)
+	for row := 0; row < rows; row++ {
+		copy(packed[row*rowSize:(row+1)*rowSize], pix[row*stride:row*stride+rowSize])
+	}
+	return packed
+}
+
+// nativeUint16 converts the big-endian 16-bit samples of the Go image types into the native byte order OpenGL reads.
+// Uploading the raw bytes would swap the high and low byte of every sample on little-endian machines
+func nativeUint16(pix []uint8) []uint16 {
+	samples := make([]uint16, len(pix)/2)
+	for i := range samples {
+		samples[i] = uint16(pix[2*i])<<8 | uint16(pix[2*i+1])
+	}
+	return samples
+}
+
+// bigEndianUint16 is the inverse of nativeUint16, it stores the samples read from OpenGL in the big-endian byte order
+// of the Go image types
+func bigEndianUint16(samples []uint16) []uint8 {
+	pix := make([]uint8, len(samples)*2)
+	for i, sample := range samples {
+		pix[2*i] = uint8(sample >> 8)
+		pix[2*i+1] = uint8(sample)
+	}
+	return pix
+}
+
+// GenerateMipmaps builds the mipmap chain from the base level and sets the min filter to GL_LINEAR_MIPMAP_LINEAR.
+// Width and Height keep reporting the size of level 0
+func (t *Texture) GenerateMipmaps() {
+	if t.id == 0 {
+		return
+	}
+	gl.BindTexture(t.target, t.id)
+	gl.TexParameteri(t.target, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	gl.GenerateMipmap(t.target)
+	gl.BindTexture(t.target, 0)
+}
+
+// SetMipLevel uploads an image as a level of the mipmap chain of a 2D texture, for the chains authored by hand.
+// The image must be max(1, floor(size/2^level)) on both axes. It's converted to the storage of the base level, like
+// SubImage, and flipped if the texture was created with FlipVertically. GL_TEXTURE_MAX_LEVEL and the min filter are
+// up to the caller, NewTextureFromMipChain sets them
+func (t *Texture) SetMipLevel(level int32, img image.Image) error {
+	if t.id == 0 {
+		return errors.New("setting mip level: the texture has been deleted")
+	}
+	if t.target != gl.TEXTURE_2D || t.format == 0 {
+		return errors.New("setting mip level: only uncompressed 2D textures are supported")
+	}
+	if level < 0 || level > 30 {
+		return fmt.Errorf("setting mip level: invalid level %d", level)
+	}
+	expectedWidth := int32(maxInt(int(t.width>>uint(level)), 1))
+	expectedHeight := int32(maxInt(int(t.height>>uint(level)), 1))
+	width, height := int32(img.Bounds().Dx()), int32(img.Bounds().Dy())
+	if width != expectedWidth || height != expectedHeight {
+		return fmt.Errorf(
+			"setting mip level: level %d must be %dx%d, got %dx%d", level, expectedWidth, expectedHeight, width, height,
+		)
+	}
+	pixels, format, pixelType, err := t.storagePixels(img)
+	if err != nil {
+		return fmt.Errorf("setting mip level: %w", err)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, level, t.internalFormat, width, height, 0, format, pixelType, pixels)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return debugCheckGLError("setting mip level")
+}
+
+// NewTextureFromMipChain creates a texture from a complete or partial mipmap chain, levels[0] being the base level
+// and every following level half the size of the previous one. With more than one level it uses trilinear filtering
+func NewTextureFromMipChain(levels []image.Image) (*Texture, error) {
+	if len(levels) == 0 {
+		return nil, errors.New("creating texture: empty mipmap chain")
+	}
+	texture, err := NewTextureFromImage(levels[0])
+	if err != nil {
+		return nil, err
+	}
+	for level := 1; level < len(levels); level++ {
+		if err := texture.SetMipLevel(int32(level), levels[level]); err != nil {
+			texture.Delete()
+			return nil, fmt.Errorf("creating texture: %w", err)
+		}
+	}
+	gl.BindTexture(gl.TEXTURE_2D, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, int32(len(levels)-1))
+	if len(levels) > 1 {
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return texture, nil
+}
+
+// Delete releases the OpenGL texture. It's safe to call it more than once and on a nil texture
+func (t *Texture) Delete() {
+	if t == nil || t.id == 0 {
+		return
+	}
+	gl.DeleteTextures(1, &t.id)
+	t.id = 0
+}
+
+// SetLabel names the texture in the GPU debuggers. It does nothing without KHR_debug
+func (t *Texture) SetLabel(name string) {
+	objectLabel(gl.TEXTURE, t.id, name)
 }
 
 // ID returns the unique OpenGL ID of this texture
@@ -131,6 +1118,16 @@ func (t *Texture) ID() uint32 {
 	return t.id
 }
 
+// Layers returns the number of layers of a texture array, 0 for the other kinds of textures
+func (t *Texture) Layers() int32 {
+	return t.layers
+}
+
+// Target returns the OpenGL target the texture binds to (e.g. gl.TEXTURE_2D, gl.TEXTURE_CUBE_MAP)
+func (t *Texture) Target() uint32 {
+	return t.target
+}
+
 // Width returns the texture width in pixels
 func (t *Texture) Width() int32 {
 	return t.width
@@ -140,3 +1137,44 @@ func (t *Texture) Width() int32 {
 func (t *Texture) Height() int32 {
 	return t.height
 }
+
+// Image returns the image the texture was created from when the RetainImage option was used, nil otherwise.
+// It's not updated by the methods that change the texture content, like SubImage
+func (t *Texture) Image() image.Image {
+	return t.image
+}
+
+// ContentUV returns the UV rectangle (u0, v0, u1, v1) covered by the image the texture was created from. It's
+// (0, 0, 1, 1) unless the texture has been padded with the PadToPowerOfTwo option
+func (t *Texture) ContentUV() mgl32.Vec4 {
+	if t.contentWidth == 0 || t.contentHeight == 0 {
+		return mgl32.Vec4{0, 0, 1, 1}
+	}
+	return mgl32.Vec4{0, 0, float32(t.contentWidth) / float32(t.width), float32(t.contentHeight) / float32(t.height)}
+}
+
+// PixelRectToUV converts a rectangle in pixels (e.g. a region returned by TextureAtlas.Build) to the UV rectangle
+// (u0, v0, u1, v1) of the texture
+func (t *Texture) PixelRectToUV(r image.Rectangle) mgl32.Vec4 {
+	width, height := float32(t.width), float32(t.height)
+	return mgl32.Vec4{
+		float32(r.Min.X) / width, float32(r.Min.Y) / height,
+		float32(r.Max.X) / width, float32(r.Max.Y) / height,
+	}
+}
+
+// PixelRectToUVInset is like PixelRectToUV, with the rectangle shrunk by half a texel on every side, so that linear
+// filtering never samples the texels of the neighbouring atlas entries
+func (t *Texture) PixelRectToUVInset(r image.Rectangle) mgl32.Vec4 {
+	halfU, halfV := 0.5/float32(t.width), 0.5/float32(t.height)
+	uv := t.PixelRectToUV(r)
+	return mgl32.Vec4{uv[0] + halfU, uv[1] + halfV, uv[2] - halfU, uv[3] - halfV}
+}
+
+// PremultipliedAlpha reports whether the color components of the texels are already multiplied by their alpha.
+// Textures created from images are premultiplied only with the PremultiplyAlpha option, whatever the source type.
+// Premultiplied textures must be blended with (GL_ONE, GL_ONE_MINUS_SRC_ALPHA) instead of
+// (GL_SRC_ALPHA, GL_ONE_MINUS_SRC_ALPHA), otherwise half-transparent texels come out darker
+func (t *Texture) PremultipliedAlpha() bool {
+	return t.premultipliedAlpha
+}
@@ -0,0 +1,72 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// NewTextureArray allocates an uninitialized gl.TEXTURE_2D_ARRAY with the specified number of layers.
+// The layers are filled with SetLayer
+func NewTextureArray(width, height, layers int32, internalFormat int32) (*Texture, error) {
+	if width <= 0 || height <= 0 || layers <= 0 {
+		return nil, fmt.Errorf("creating texture array: invalid size %dx%dx%d", width, height, layers)
+	}
+	texture := &Texture{
+		target:         gl.TEXTURE_2D_ARRAY,
+		width:          width,
+		height:         height,
+		layers:         layers,
+		internalFormat: internalFormat,
+	}
+	texture.format, texture.pixelType = clientFormat(internalFormat)
+	gl.GenTextures(1, &texture.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexImage3D(
+		gl.TEXTURE_2D_ARRAY, 0, internalFormat, width, height, layers,
+		0, texture.format, texture.pixelType, nil,
+	)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+
+	return texture, nil
+}
+
+// SetLayer uploads an image into one of the layers of a texture array. The image must have the size of the array,
+// it's converted to the storage of the array like SubImage does
+func (t *Texture) SetLayer(layer int32, img image.Image) error {
+	if t.target != gl.TEXTURE_2D_ARRAY {
+		return errors.New("setting texture layer: the texture is not an array")
+	}
+	if layer < 0 || layer >= t.layers {
+		return fmt.Errorf("setting texture layer: layer %d out of range [0, %d)", layer, t.layers)
+	}
+	width := int32(img.Bounds().Dx())
+	height := int32(img.Bounds().Dy())
+	if width != t.width || height != t.height {
+		return fmt.Errorf(
+			"setting texture layer: image is %dx%d, expected %dx%d", width, height, t.width, t.height,
+		)
+	}
+
+	pixels, format, pixelType, err := t.storagePixels(img)
+	if err != nil {
+		return fmt.Errorf("setting texture layer: %w", err)
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, t.id)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexSubImage3D(
+		gl.TEXTURE_2D_ARRAY, 0, 0, 0, layer, width, height, 1,
+		format, pixelType, pixels,
+	)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+	return debugCheckGLError("setting texture layer")
+}
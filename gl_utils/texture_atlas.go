@@ -0,0 +1,120 @@
+package gl_utils
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+type atlasEntry struct {
+	name  string
+	image image.Image
+}
+
+// TextureAtlas packs many small images into a single texture, to draw them all with one bind
+type TextureAtlas struct {
+	maxSize    int
+	powerOfTwo bool
+	entries    []atlasEntry
+	names      map[string]bool
+}
+
+// NewTextureAtlas creates an empty atlas whose texture can't be wider or taller than maxSize pixels.
+// With powerOfTwo the texture size is rounded up to powers of two
+func NewTextureAtlas(maxSize int, powerOfTwo bool) *TextureAtlas {
+	return &TextureAtlas{
+		maxSize:    maxSize,
+		powerOfTwo: powerOfTwo,
+		names:      make(map[string]bool),
+	}
+}
+
+// Add queues an image to be packed. Names must be unique
+func (a *TextureAtlas) Add(name string, img image.Image) error {
+	if a.names[name] {
+		return fmt.Errorf("adding %q to the atlas: name already used", name)
+	}
+	size := img.Bounds().Size()
+	if size.X > a.maxSize || size.Y > a.maxSize {
+		return fmt.Errorf("adding %q to the atlas: %dx%d is larger than the atlas", name, size.X, size.Y)
+	}
+	a.names[name] = true
+	a.entries = append(a.entries, atlasEntry{name: name, image: img})
+	return nil
+}
+
+// Build packs all the images added so far and uploads them into a texture. It returns the pixel rectangle occupied
+// by each image, keyed by name. The images are placed in rows (shelf packing) sorted by decreasing height.
+// It returns an error if the images don't fit in maxSize x maxSize
+func (a *TextureAtlas) Build() (*Texture, map[string]image.Rectangle, error) {
+	if len(a.entries) == 0 {
+		return nil, nil, fmt.Errorf("building atlas: no images added")
+	}
+	entries := make([]atlasEntry, len(a.entries))
+	copy(entries, a.entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].image.Bounds().Dy() > entries[j].image.Bounds().Dy()
+	})
+
+	// Start from the narrowest square-ish width and widen the atlas until everything fits
+	area := 0
+	width := 0
+	for _, entry := range entries {
+		size := entry.image.Bounds().Size()
+		area += size.X * size.Y
+		width = maxInt(width, size.X)
+	}
+	width = maxInt(width, int(math.Ceil(math.Sqrt(float64(area)))))
+	for {
+		if a.powerOfTwo {
+			width = int(NextPowerOfTwo(int32(width)))
+		}
+		width = minInt(width, a.maxSize)
+		rects, height := shelfPack(entries, width)
+		if a.powerOfTwo {
+			height = int(NextPowerOfTwo(int32(height)))
+		}
+		if height <= a.maxSize {
+			return a.upload(entries, rects, width, height)
+		}
+		if width == a.maxSize {
+			return nil, nil, fmt.Errorf("building atlas: the images don't fit in %dx%d", a.maxSize, a.maxSize)
+		}
+		width *= 2
+	}
+}
+
+// shelfPack places the images in rows of the given width, returning their rectangles and the total height
+func shelfPack(entries []atlasEntry, width int) ([]image.Rectangle, int) {
+	rects := make([]image.Rectangle, len(entries))
+	x, y, shelfHeight := 0, 0, 0
+	for i, entry := range entries {
+		size := entry.image.Bounds().Size()
+		if x+size.X > width {
+			x = 0
+			y += shelfHeight
+			shelfHeight = 0
+		}
+		rects[i] = image.Rect(x, y, x+size.X, y+size.Y)
+		x += size.X
+		shelfHeight = maxInt(shelfHeight, size.Y)
+	}
+	return rects, y + shelfHeight
+}
+
+// upload composes the packed images and creates the texture
+func (a *TextureAtlas) upload(entries []atlasEntry, rects []image.Rectangle, width, height int) (*Texture, map[string]image.Rectangle, error) {
+	atlasImage := image.NewNRGBA(image.Rect(0, 0, width, height))
+	regions := make(map[string]image.Rectangle, len(entries))
+	for i, entry := range entries {
+		draw.Draw(atlasImage, rects[i], entry.image, entry.image.Bounds().Min, draw.Src)
+		regions[entry.name] = rects[i]
+	}
+	texture, err := NewTextureFromImage(atlasImage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building atlas: %w", err)
+	}
+	return texture, regions, nil
+}
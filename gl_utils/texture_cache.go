@@ -0,0 +1,62 @@
+package gl_utils
+
+type cachedTexture struct {
+	texture    *Texture
+	references int
+}
+
+// TextureCache loads every texture file once and shares it between all the users.
+// It's not goroutine-safe: like every other GL call it must be used from the thread owning the GL context
+type TextureCache struct {
+	textures map[string]*cachedTexture
+}
+
+// NewTextureCache creates an empty texture cache
+func NewTextureCache() *TextureCache {
+	return &TextureCache{
+		textures: make(map[string]*cachedTexture),
+	}
+}
+
+// Get returns the texture loaded from filePath, loading it the first time it's requested.
+// Every call must be balanced by a call to Release once the texture is not needed anymore
+func (c *TextureCache) Get(filePath string) (*Texture, error) {
+	if cached, found := c.textures[filePath]; found {
+		cached.references++
+		return cached.texture, nil
+	}
+
+	texture, err := NewTextureFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	c.textures[filePath] = &cachedTexture{texture: texture, references: 1}
+	return texture, nil
+}
+
+// Release gives back a texture obtained with Get. The texture is deleted when nobody is using it anymore
+func (c *TextureCache) Release(filePath string) {
+	cached, found := c.textures[filePath]
+	if !found {
+		return
+	}
+	cached.references--
+	if cached.references <= 0 {
+		cached.texture.Delete()
+		delete(c.textures, filePath)
+	}
+}
+
+// Len returns the number of textures currently in the cache
+func (c *TextureCache) Len() int {
+	return len(c.textures)
+}
+
+// Clear deletes all the cached textures, including the ones that haven't been released yet.
+// Any leftover reference to them becomes invalid, so call it only when tearing down the scene
+func (c *TextureCache) Clear() {
+	for filePath, cached := range c.textures {
+		cached.texture.Delete()
+		delete(c.textures, filePath)
+	}
+}
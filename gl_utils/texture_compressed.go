@@ -0,0 +1,213 @@
+package gl_utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/bits"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// compressedBlockSize returns the size in bytes of a 4x4 block of a supported compressed format
+func compressedBlockSize(internalFormat uint32) (int, bool) {
+	switch internalFormat {
+	case gl.COMPRESSED_RGB_S3TC_DXT1_EXT, gl.COMPRESSED_RGBA_S3TC_DXT1_EXT:
+		return 8, true
+	case gl.COMPRESSED_RGBA_S3TC_DXT3_EXT, gl.COMPRESSED_RGBA_S3TC_DXT5_EXT:
+		return 16, true
+	}
+	return 0, false
+}
+
+// compressedImageSize returns the size in bytes of an image of the given compressed format
+func compressedImageSize(internalFormat uint32, width, height int32) (int, error) {
+	blockSize, ok := compressedBlockSize(internalFormat)
+	if !ok {
+		return 0, fmt.Errorf("unsupported compressed format 0x%X", internalFormat)
+	}
+	blocksX := (int(width) + 3) / 4
+	blocksY := (int(height) + 3) / 4
+	return blocksX * blocksY * blockSize, nil
+}
+
+// NewCompressedTexture uploads block compressed data. The supported formats are the S3TC ones
+// (gl.COMPRESSED_RGB_S3TC_DXT1_EXT, gl.COMPRESSED_RGBA_S3TC_DXT1_EXT, gl.COMPRESSED_RGBA_S3TC_DXT3_EXT and
+// gl.COMPRESSED_RGBA_S3TC_DXT5_EXT), and data must hold exactly one level of the given size
+func NewCompressedTexture(width, height int32, internalFormat uint32, data []byte) (*Texture, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("creating compressed texture: invalid size %dx%d", width, height)
+	}
+	expectedSize, err := compressedImageSize(internalFormat, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("creating compressed texture: %w", err)
+	}
+	if len(data) != expectedSize {
+		return nil, fmt.Errorf(
+			"creating compressed texture: got %d bytes, expected %d for %dx%d", len(data), expectedSize, width, height,
+		)
+	}
+
+	texture := &Texture{
+		target:         gl.TEXTURE_2D,
+		width:          width,
+		height:         height,
+		internalFormat: int32(internalFormat),
+	}
+	gl.GenTextures(1, &texture.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.CompressedTexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, int32(len(data)), gl.Ptr(data))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return texture, nil
+}
+
+// ddsHeader is the header following the "DDS " magic number of a DDS file
+type ddsHeader struct {
+	Size              uint32
+	Flags             uint32
+	Height            uint32
+	Width             uint32
+	PitchOrLinearSize uint32
+	Depth             uint32
+	MipMapCount       uint32
+	Reserved1         [11]uint32
+	PixelFormat       struct {
+		Size        uint32
+		Flags       uint32
+		FourCC      [4]byte
+		RGBBitCount uint32
+		RBitMask    uint32
+		GBitMask    uint32
+		BBitMask    uint32
+		ABitMask    uint32
+	}
+	Caps      uint32
+	Caps2     uint32
+	Caps3     uint32
+	Caps4     uint32
+	Reserved2 uint32
+}
+
+const (
+	ddsFlagMipMapCount       = 0x20000
+	ddsPixelFormatFlagFourCC = 0x4
+)
+
+// NewTextureFromDDS loads a DXT1, DXT3 or DXT5 compressed DDS file, including its mipmaps.
+// DDS stores the rows top to bottom, so the image ends up vertically flipped compared to OpenGL conventions.
+// Other pixel formats (uncompressed, DX10 extended headers, cubemaps) return an error
+func NewTextureFromDDS(r io.Reader) (*Texture, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("loading DDS: %w", err)
+	}
+	if string(magic[:]) != "DDS " {
+		return nil, errors.New("loading DDS: not a DDS file")
+	}
+	var header ddsHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("loading DDS: %w", err)
+	}
+	if header.Size != 124 {
+		return nil, fmt.Errorf("loading DDS: invalid header size %d", header.Size)
+	}
+	if header.PixelFormat.Flags&ddsPixelFormatFlagFourCC == 0 {
+		return nil, errors.New("loading DDS: only compressed pixel formats are supported")
+	}
+
+	var internalFormat uint32
+	switch fourCC := string(header.PixelFormat.FourCC[:]); fourCC {
+	case "DXT1":
+		internalFormat = gl.COMPRESSED_RGBA_S3TC_DXT1_EXT
+	case "DXT3":
+		internalFormat = gl.COMPRESSED_RGBA_S3TC_DXT3_EXT
+	case "DXT5":
+		internalFormat = gl.COMPRESSED_RGBA_S3TC_DXT5_EXT
+	default:
+		return nil, fmt.Errorf("loading DDS: unsupported pixel format %q", fourCC)
+	}
+
+	// The header comes from outside, it's validated before any allocation
+	width := int32(header.Width)
+	height := int32(header.Height)
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("loading DDS: invalid size %dx%d", width, height)
+	}
+	if err := checkTextureSize(width, height); err != nil {
+		return nil, fmt.Errorf("loading DDS: %w", err)
+	}
+	levels := int32(1)
+	if header.Flags&ddsFlagMipMapCount != 0 && header.MipMapCount > 1 {
+		maxLevels := uint32(bits.Len32(uint32(maxInt(int(width), int(height)))))
+		if header.MipMapCount > maxLevels {
+			return nil, fmt.Errorf(
+				"loading DDS: %d mip levels, a %dx%d texture has at most %d", header.MipMapCount, width, height, maxLevels,
+			)
+		}
+		levels = int32(header.MipMapCount)
+	}
+
+	levelSizes := make([]int, levels)
+	totalSize := 0
+	for level, levelWidth, levelHeight := int32(0), width, height; level < levels; level++ {
+		size, err := compressedImageSize(internalFormat, levelWidth, levelHeight)
+		if err != nil {
+			return nil, fmt.Errorf("loading DDS: %w", err)
+		}
+		levelSizes[level] = size
+		totalSize += size
+		levelWidth = int32(maxInt(int(levelWidth)/2, 1))
+		levelHeight = int32(maxInt(int(levelHeight)/2, 1))
+	}
+	// Reading through a limited reader grows the buffer with the data actually there, so a truncated file doesn't
+	// allocate the size announced by its header. All the levels are read before touching any GL state
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(totalSize)))
+	if err != nil {
+		return nil, fmt.Errorf("loading DDS: %w", err)
+	}
+	if len(data) != totalSize {
+		return nil, fmt.Errorf("loading DDS: got %d bytes of texel data, expected %d", len(data), totalSize)
+	}
+	levelData := make([][]byte, levels)
+	for level, size := range levelSizes {
+		levelData[level], data = data[:size], data[size:]
+	}
+
+	texture := &Texture{
+		target:         gl.TEXTURE_2D,
+		width:          width,
+		height:         height,
+		internalFormat: int32(internalFormat),
+	}
+	var minFilter int32 = gl.LINEAR
+	if levels > 1 {
+		minFilter = gl.LINEAR_MIPMAP_LINEAR
+	}
+	gl.GenTextures(1, &texture.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture.id)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAX_LEVEL, levels-1)
+	for level, levelWidth, levelHeight := int32(0), width, height; level < levels; level++ {
+		data := levelData[level]
+		gl.CompressedTexImage2D(
+			gl.TEXTURE_2D, level, internalFormat, levelWidth, levelHeight, 0, int32(len(data)), gl.Ptr(data),
+		)
+		levelWidth = int32(maxInt(int(levelWidth)/2, 1))
+		levelHeight = int32(maxInt(int(levelHeight)/2, 1))
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	return texture, nil
+}
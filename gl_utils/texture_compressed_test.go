@@ -0,0 +1,48 @@
+package gl_utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// ddsFile builds a DXT5 DDS file of the given size and mip count, followed by dataSize bytes of texel data
+func ddsFile(width, height, mipMapCount uint32, dataSize int) []byte {
+	header := ddsHeader{Size: 124, Flags: ddsFlagMipMapCount, Width: width, Height: height, MipMapCount: mipMapCount}
+	header.PixelFormat.Size = 32
+	header.PixelFormat.Flags = ddsPixelFormatFlagFourCC
+	copy(header.PixelFormat.FourCC[:], "DXT5")
+	var file bytes.Buffer
+	file.WriteString("DDS ")
+	_ = binary.Write(&file, binary.LittleEndian, &header)
+	file.Write(make([]byte, dataSize))
+	return file.Bytes()
+}
+
+func TestMalformedDDSIsRejected(t *testing.T) {
+	// A cached limit keeps MaxTextureSize from querying the context
+	defer func(previous int32) { maxTextureSize = previous }(maxTextureSize)
+	maxTextureSize = 4096
+
+	// 16x16 DXT5 holds 5 levels: 256, 64, 16, 16 and 16 bytes
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"huge mip count", ddsFile(16, 16, 0xFFFFFFFF, 368)},
+		{"mip count above the maximum", ddsFile(16, 16, 0x7FFFFFFF, 368)},
+		{"one level too many", ddsFile(16, 16, 6, 384)},
+		{"oversize", ddsFile(8192, 16, 1, 0)},
+		{"negative size", ddsFile(0x80000000, 16, 1, 0)},
+		{"zero size", ddsFile(0, 16, 1, 0)},
+		{"truncated level", ddsFile(16, 16, 1, 255)},
+		{"truncated mipmaps", ddsFile(16, 16, 5, 367)},
+		{"truncated header", ddsFile(16, 16, 1, 0)[:64]},
+	}
+	// The validation happens before any OpenGL call
+	for _, test := range tests {
+		if _, err := NewTextureFromDDS(bytes.NewReader(test.file)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
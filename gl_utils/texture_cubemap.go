@@ -0,0 +1,65 @@
+package gl_utils
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// NewCubemapFromImages creates a cubemap texture. The faces are in the order +X, -X, +Y, -Y, +Z, -Z and must all be
+// non-empty squares of the same size, within MaxTextureSize
+func NewCubemapFromImages(faces [6]image.Image) (*Texture, error) {
+	for i, face := range faces {
+		if face == nil {
+			return nil, fmt.Errorf("creating cubemap: face %d is missing", i)
+		}
+	}
+	size := faces[0].Bounds().Size()
+	if size.X != size.Y {
+		return nil, fmt.Errorf("creating cubemap: faces must be square, got %dx%d", size.X, size.Y)
+	}
+	if size.X <= 0 {
+		return nil, fmt.Errorf("creating cubemap: invalid face size %dx%d", size.X, size.Y)
+	}
+	if err := checkTextureSize(int32(size.X), int32(size.Y)); err != nil {
+		return nil, fmt.Errorf("creating cubemap: %w", err)
+	}
+
+	faceData := make([][]uint8, len(faces))
+	for i, face := range faces {
+		if face.Bounds().Size() != size {
+			return nil, fmt.Errorf(
+				"creating cubemap: face %d is %dx%d, expected %dx%d",
+				i, face.Bounds().Dx(), face.Bounds().Dy(), size.X, size.Y,
+			)
+		}
+		faceData[i] = rgbaPixelData(face, false)
+	}
+
+	texture := &Texture{
+		target:         gl.TEXTURE_CUBE_MAP,
+		width:          int32(size.X),
+		height:         int32(size.Y),
+		internalFormat: gl.RGBA,
+		format:         gl.RGBA,
+		pixelType:      gl.UNSIGNED_BYTE,
+	}
+	gl.GenTextures(1, &texture.id)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, texture.id)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_CUBE_MAP, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+	for i, pixelData := range faceData {
+		gl.TexImage2D(
+			gl.TEXTURE_CUBE_MAP_POSITIVE_X+uint32(i), 0, texture.internalFormat, texture.width, texture.height,
+			0, texture.format, texture.pixelType, gl.Ptr(pixelData),
+		)
+	}
+	gl.BindTexture(gl.TEXTURE_CUBE_MAP, 0)
+
+	return texture, nil
+}
@@ -0,0 +1,44 @@
+package gl_utils
+
+import (
+	"image"
+	"testing"
+)
+
+// cubemapFaces returns six faces of the given size, face odd being different
+func cubemapFaces(size int, odd int, oddBounds image.Rectangle) [6]image.Image {
+	var faces [6]image.Image
+	for i := range faces {
+		faces[i] = image.NewNRGBA(image.Rect(0, 0, size, size))
+	}
+	if odd >= 0 {
+		faces[odd] = image.NewNRGBA(oddBounds)
+	}
+	return faces
+}
+
+func TestInvalidCubemapFacesAreRejected(t *testing.T) {
+	// A cached limit keeps MaxTextureSize from querying the context
+	defer func(previous int32) { maxTextureSize = previous }(maxTextureSize)
+	maxTextureSize = 1024
+
+	missing := cubemapFaces(16, -1, image.Rectangle{})
+	missing[3] = nil
+	tests := []struct {
+		name  string
+		faces [6]image.Image
+	}{
+		{"not square", cubemapFaces(16, 0, image.Rect(0, 0, 16, 8))},
+		{"different size", cubemapFaces(16, 4, image.Rect(0, 0, 8, 8))},
+		{"other face not square", cubemapFaces(16, 5, image.Rect(0, 0, 16, 17))},
+		{"empty", cubemapFaces(0, -1, image.Rectangle{})},
+		{"oversize", cubemapFaces(2048, -1, image.Rectangle{})},
+		{"missing face", missing},
+	}
+	// The faces are checked before any OpenGL call
+	for _, test := range tests {
+		if _, err := NewCubemapFromImages(test.faces); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build gl_context
+// +build gl_context
+
+package gl_utils
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// fragmentShaderStretched samples the texture from -1 to 2 horizontally, so that the sides fall outside of it
+const fragmentShaderStretched = `
+        #version 410 core
+
+        in vec2 uv_out;
+        out vec4 color;
+
+        uniform sampler2D tex;
+
+        void main() {
+            color = texture(tex, vec2(uv_out.x * 3 - 1, 0.5));
+        }
+        `
+
+func TestBorderColor(t *testing.T) {
+	withContext(t)
+	red := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := 0; i < len(red.Pix); i += 4 {
+		copy(red.Pix[i:], []uint8{255, 0, 0, 255})
+	}
+	opts := TextureOptions{
+		MinFilter: gl.NEAREST,
+		MagFilter: gl.NEAREST,
+		WrapS:     gl.CLAMP_TO_BORDER,
+		WrapT:     gl.CLAMP_TO_BORDER,
+	}
+	texture, err := NewTextureFromImageWithOptions(red, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer texture.Delete()
+	texture.SetBorderColor(mgl32.Vec4{0, 1, 0, 1})
+
+	shader, err := NewShaderProgramFromSource(VertexShaderFullscreen, fragmentShaderStretched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shader.Delete()
+	// 4 pixels sampling at U -0.625, 0.125, 0.875 and 1.625
+	framebuffer := renderTarget(t, 4, 1, DepthAttachmentNone)
+	defer framebuffer.Delete()
+	DrawFullscreen(shader, texture)
+	framebuffer.Unbind()
+
+	expected := []color.NRGBA{{G: 255, A: 255}, {R: 255, A: 255}, {R: 255, A: 255}, {G: 255, A: 255}}
+	for x, want := range expected {
+		if got := renderedPixel(t, framebuffer, x, 0); got != want {
+			t.Errorf("pixel %d: got %v, expected %v", x, got, want)
+		}
+	}
+}
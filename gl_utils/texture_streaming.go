@@ -0,0 +1,104 @@
+package gl_utils
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// StreamingTexture a RGBA texture updated every frame (e.g. video or webcam frames) through two pixel buffer objects:
+// while the GPU copies a frame from one of them into the texture, the next frame is written into the other one, so
+// the upload doesn't stall the pipeline. The price is one frame of latency
+type StreamingTexture struct {
+	texture *Texture
+	pbos    [2]uint32
+	// PBO holding the last frame written, and whether it's still to be copied into the texture
+	current int
+	pending bool
+}
+
+// NewStreamingTexture allocates the texture and its two pixel buffers
+func NewStreamingTexture(width, height int32) (*StreamingTexture, error) {
+	texture, err := NewEmptyTexture(int(width), int(height), gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE)
+	if err != nil {
+		return nil, fmt.Errorf("creating streaming texture: %w", err)
+	}
+	s := &StreamingTexture{texture: texture}
+	gl.GenBuffers(2, &s.pbos[0])
+	for _, pbo := range s.pbos {
+		gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, pbo)
+		gl.BufferData(gl.PIXEL_UNPACK_BUFFER, s.frameSize(), nil, gl.STREAM_DRAW)
+	}
+	gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+	return s, nil
+}
+
+// Update queues a new frame, which must have the size of the texture. The texture shows the frame passed to the
+// previous Update, the new one becomes visible at the next call
+func (s *StreamingTexture) Update(img image.Image) error {
+	if s.texture.id == 0 {
+		return errors.New("updating streaming texture: the texture has been deleted")
+	}
+	width, height := int32(img.Bounds().Dx()), int32(img.Bounds().Dy())
+	if width != s.texture.width || height != s.texture.height {
+		return fmt.Errorf(
+			"updating streaming texture: frame %dx%d doesn't match the texture %dx%d",
+			width, height, s.texture.width, s.texture.height,
+		)
+	}
+	pixelData := rgbaPixelData(img, s.texture.premultipliedAlpha)
+
+	// With a PBO bound, TexSubImage2D reads from the buffer and returns without waiting for the copy
+	if s.pending {
+		gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, s.pbos[s.current])
+		gl.BindTexture(gl.TEXTURE_2D, s.texture.id)
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, width, height, gl.RGBA, gl.UNSIGNED_BYTE, gl.PtrOffset(0))
+		gl.BindTexture(gl.TEXTURE_2D, 0)
+	}
+
+	// Orphaning the storage before mapping it lets the driver hand over a fresh buffer instead of waiting for the
+	// GPU to be done with the old one, then the frame is written straight into the mapped memory
+	s.current = 1 - s.current
+	size := s.frameSize()
+	gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, s.pbos[s.current])
+	defer gl.BindBuffer(gl.PIXEL_UNPACK_BUFFER, 0)
+	gl.BufferData(gl.PIXEL_UNPACK_BUFFER, size, nil, gl.STREAM_DRAW)
+	mapping := gl.MapBufferRange(gl.PIXEL_UNPACK_BUFFER, 0, size, gl.MAP_WRITE_BIT|gl.MAP_INVALIDATE_BUFFER_BIT)
+	if mapping == nil {
+		s.pending = false
+		return errors.New("updating streaming texture: the pixel buffer can't be mapped")
+	}
+	copy((*[1 << 30]uint8)(mapping)[:size:size], pixelData)
+	if !gl.UnmapBuffer(gl.PIXEL_UNPACK_BUFFER) {
+		// The content of the buffer got lost while it was mapped, e.g. after a display mode change
+		s.pending = false
+		return errors.New("updating streaming texture: the pixel buffer content has been lost")
+	}
+	s.pending = true
+	return nil
+}
+
+// frameSize returns the size in bytes of a RGBA frame, at most 1GB (a 16384x16384 texture)
+func (s *StreamingTexture) frameSize() int {
+	return int(s.texture.width) * int(s.texture.height) * 4
+}
+
+// Texture returns the texture to sample
+func (s *StreamingTexture) Texture() *Texture {
+	return s.texture
+}
+
+// Delete releases the texture and the pixel buffers. It's safe to call it more than once and on a nil
+// StreamingTexture
+func (s *StreamingTexture) Delete() {
+	if s == nil {
+		return
+	}
+	s.texture.Delete()
+	if s.pbos[0] != 0 {
+		gl.DeleteBuffers(2, &s.pbos[0])
+		s.pbos = [2]uint32{}
+	}
+}
@@ -0,0 +1,306 @@
+package gl_utils
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// blendOverBlack composites a texel over opaque black with the blending that matches its alpha mode:
+// (GL_SRC_ALPHA, GL_ONE_MINUS_SRC_ALPHA) for straight alpha, (GL_ONE, GL_ONE_MINUS_SRC_ALPHA) for premultiplied
+func blendOverBlack(texel []uint8, premultiplied bool) float64 {
+	src, alpha := float64(texel[0])/255, float64(texel[3])/255
+	if premultiplied {
+		return src
+	}
+	return src * alpha
+}
+
+func TestAlphaModeBlending(t *testing.T) {
+	halfWhite := color.NRGBA{R: 255, G: 255, B: 255, A: 128}
+	rgba := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	rgba.Set(0, 0, halfWhite)
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	nrgba.Set(0, 0, halfWhite)
+
+	for _, premultiplied := range []bool{false, true} {
+		for _, src := range []image.Image{rgba, nrgba} {
+			texel := imagePix(alphaModeImage(src, premultiplied))
+			got := blendOverBlack(texel, premultiplied)
+			if got < 127.0/255 || got > 129.0/255 {
+				t.Errorf("%T premultiplied=%v: 50%% white over black gives %.3f, expected 0.5", src, premultiplied, got)
+			}
+		}
+	}
+}
+
+// opaqueImage fills an image with opaque gray
+func opaqueImage(img draw.Image) draw.Image {
+	return drawnImage(img, image.NewUniform(color.Gray{Y: 100}))
+}
+
+func TestAlphaModeImage(t *testing.T) {
+	bounds := image.Rect(0, 0, 2, 1)
+	tests := []struct {
+		src           image.Image
+		premultiplied bool
+		expected      string
+		same          bool
+	}{
+		{image.NewRGBA(bounds), true, "*image.RGBA", true},
+		{image.NewRGBA(bounds), false, "*image.NRGBA", false},
+		{image.NewNRGBA(bounds), false, "*image.NRGBA", true},
+		{image.NewNRGBA(bounds), true, "*image.RGBA", false},
+		{opaqueImage(image.NewRGBA(bounds)), false, "*image.RGBA", true},
+		{opaqueImage(image.NewNRGBA(bounds)), true, "*image.NRGBA", true},
+		{opaqueImage(image.NewRGBA64(bounds)), false, "*image.RGBA64", true},
+		{opaqueImage(image.NewNRGBA64(bounds)), true, "*image.NRGBA64", true},
+		{image.NewRGBA64(bounds), true, "*image.RGBA64", true},
+		{image.NewRGBA64(bounds), false, "*image.NRGBA64", false},
+		{image.NewNRGBA64(bounds), true, "*image.RGBA64", false},
+		{image.NewGray(bounds), true, "*image.Gray", true},
+		{image.NewGray16(bounds), false, "*image.Gray16", true},
+		{image.NewYCbCr(bounds, image.YCbCrSubsampleRatio420), false, "*image.RGBA", false},
+		{image.NewPaletted(bounds, color.Palette{color.Black}), false, "*image.NRGBA", false},
+		{image.NewPaletted(bounds, color.Palette{color.Black}), true, "*image.RGBA", false},
+	}
+	for _, test := range tests {
+		converted := alphaModeImage(test.src, test.premultiplied)
+		if got := fmt.Sprintf("%T", converted); got != test.expected {
+			t.Errorf("%T premultiplied=%v: got %s, expected %s", test.src, test.premultiplied, got, test.expected)
+		}
+		if same := converted == test.src; same != test.same {
+			t.Errorf("%T premultiplied=%v: returned the source %v, expected %v",
+				test.src, test.premultiplied, same, test.same)
+		}
+	}
+}
+
+func TestHalfTransparentRedUpload(t *testing.T) {
+	// image.RGBA stores the half transparent red premultiplied, image.NRGBA straight
+	rgba := &image.RGBA{Pix: []uint8{128, 0, 0, 128}, Stride: 4, Rect: image.Rect(0, 0, 1, 1)}
+	nrgba := &image.NRGBA{Pix: []uint8{255, 0, 0, 128}, Stride: 4, Rect: image.Rect(0, 0, 1, 1)}
+	for _, src := range []image.Image{rgba, nrgba} {
+		if texel := imagePix(alphaModeImage(src, true)); string(texel) != string([]uint8{128, 0, 0, 128}) {
+			t.Errorf("%T premultiplied: got %v, expected [128 0 0 128]", src, texel)
+		}
+		if texel := imagePix(alphaModeImage(src, false)); string(texel) != string([]uint8{255, 0, 0, 128}) {
+			t.Errorf("%T straight: got %v, expected [255 0 0 128]", src, texel)
+		}
+	}
+}
+
+func TestFlippedRows(t *testing.T) {
+	tests := []struct {
+		pix      []uint8
+		stride   int
+		expected []uint8
+	}{
+		{[]uint8{1, 2, 3, 4, 5, 6}, 2, []uint8{5, 6, 3, 4, 1, 2}},
+		{[]uint8{1, 2, 3, 4, 5, 6, 7, 8}, 2, []uint8{7, 8, 5, 6, 3, 4, 1, 2}},
+		{[]uint8{1, 2, 3}, 3, []uint8{1, 2, 3}},
+	}
+	for _, test := range tests {
+		source := append([]uint8(nil), test.pix...)
+		if got := flippedRows(test.pix, test.stride); string(got) != string(test.expected) {
+			t.Errorf("flippedRows(%v, %d) = %v, expected %v", source, test.stride, got, test.expected)
+		}
+		if string(test.pix) != string(source) {
+			t.Errorf("flippedRows modified its source: %v", test.pix)
+		}
+		flipRows(test.pix, test.stride)
+		if string(test.pix) != string(test.expected) {
+			t.Errorf("flipRows(%v, %d) = %v, expected %v", source, test.stride, test.pix, test.expected)
+		}
+	}
+}
+
+func TestFlipVerticallyUpload(t *testing.T) {
+	// Three rows of one pixel: red, green, blue
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 1, 3))
+	paletted := image.NewPaletted(image.Rect(0, 0, 1, 3), color.Palette{
+		color.NRGBA{R: 255, A: 255}, color.NRGBA{G: 255, A: 255}, color.NRGBA{B: 255, A: 255},
+	})
+	for y, c := range paletted.Palette {
+		nrgba.Set(0, y, c)
+		paletted.SetColorIndex(0, y, uint8(y))
+	}
+	texture := &Texture{format: gl.RGBA, pixelType: gl.UNSIGNED_BYTE, flipped: true}
+	expected := []uint8{0, 0, 255, 255, 0, 255, 0, 255, 255, 0, 0, 255}
+	for _, src := range []image.Image{nrgba, paletted} {
+		pixels, _, _, err := texture.storagePixels(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := (*[12]uint8)(pixels)[:]; string(got) != string(expected) {
+			t.Errorf("%T: got %v, expected the rows bottom to top %v", src, got, expected)
+		}
+	}
+	if string(nrgba.Pix[:4]) != string([]uint8{255, 0, 0, 255}) {
+		t.Errorf("the source image has been modified: %v", nrgba.Pix)
+	}
+}
+
+func TestSolidColorImage(t *testing.T) {
+	halfRed := color.NRGBA{R: 255, A: 128}
+	expected := color.RGBAModel.Convert(halfRed).(color.RGBA)
+	for _, size := range []image.Point{{1, 1}, {3, 5}, {7, 1}, {1, 9}, {17, 3}} {
+		img := solidColorImage(size.X, size.Y, halfRed)
+		if img.Bounds().Size() != size {
+			t.Fatalf("%v: got an image of %v", size, img.Bounds().Size())
+		}
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				if got := img.RGBAAt(x, y); got != expected {
+					t.Fatalf("%v: pixel %d,%d is %v, expected %v", size, x, y, got, expected)
+				}
+			}
+		}
+		// Stored premultiplied, the texels read back as an image.RGBA give the same color
+		if got := imagePix(alphaModeImage(img, true)); string(got[len(got)-4:]) != string(img.Pix[:4]) {
+			t.Errorf("%v: the last texel is %v, expected %v", size, got[len(got)-4:], img.Pix[:4])
+		}
+	}
+}
+
+func TestNativeUint16(t *testing.T) {
+	// image.Gray16 and image.RGBA64 store the samples big-endian
+	pix := []uint8{0x12, 0x34, 0xFF, 0x00}
+	samples := nativeUint16(pix)
+	if len(samples) != 2 || samples[0] != 0x1234 || samples[1] != 0xFF00 {
+		t.Errorf("nativeUint16(%v) = %#x, expected [0x1234 0xff00]", pix, samples)
+	}
+	if back := bigEndianUint16(samples); string(back) != string(pix) {
+		t.Errorf("bigEndianUint16(%#x) = %v, expected %v", samples, back, pix)
+	}
+}
+
+func TestMidGray16RoundTrip(t *testing.T) {
+	midGray := color.RGBA64{R: 0x8000, G: 0x8000, B: 0x8000, A: 0xFFFF}
+	img := image.NewRGBA64(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			img.SetRGBA64(x, y, midGray)
+		}
+	}
+	internalFormat, format, pixelType, err := GLFormatForImage(img)
+	if err != nil || internalFormat != gl.RGBA16 || pixelType != gl.UNSIGNED_SHORT {
+		t.Fatalf("GLFormatForImage: 0x%X 0x%X, error %v, expected RGBA16 with 16-bit components",
+			internalFormat, pixelType, err)
+	}
+
+	// What the driver receives, then what ToImage16 makes of the samples the driver returns
+	texture := &Texture{width: 3, height: 2, format: format, pixelType: pixelType}
+	pixels, _, _, err := texture.storagePixels(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := (*[3 * 2 * 4]uint16)(pixels)[:]
+	if samples[0] != 0x8000 {
+		t.Fatalf("the driver receives 0x%X, expected 0x8000", samples[0])
+	}
+	back := &image.NRGBA64{Pix: bigEndianUint16(samples), Stride: 3 * 8, Rect: img.Rect}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			if got := back.RGBA64At(x, y); got != midGray {
+				t.Fatalf("pixel %d,%d: got %v, expected %v", x, y, got, midGray)
+			}
+		}
+	}
+}
+
+// BenchmarkNewTextureFromYCbCr measures the CPU side of uploading a 4K JPEG with straight alpha, through the
+// conversions of the loader: alphaModeImage, then storagePixels. It's compared to the generic path the image would
+// take without the image.YCbCr case
+func BenchmarkNewTextureFromYCbCr(b *testing.B) {
+	img := image.NewYCbCr(image.Rect(0, 0, 3840, 2160), image.YCbCrSubsampleRatio420)
+	for i := range img.Y {
+		img.Y[i] = uint8(i)
+	}
+	for i := range img.Cb {
+		img.Cb[i], img.Cr[i] = uint8(i*3), uint8(i*7)
+	}
+	texture := &Texture{format: gl.RGBA, pixelType: gl.UNSIGNED_BYTE}
+	b.Run("YCbCr", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, _, err := texture.storagePixels(alphaModeImage(img, false)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			nrgba := drawnImage(image.NewNRGBA(img.Bounds()), img)
+			if _, _, _, err := texture.storagePixels(alphaModeImage(nrgba, false)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestYCbCrIsConvertedOnce(t *testing.T) {
+	img := image.NewYCbCr(image.Rect(0, 0, 4, 2), image.YCbCrSubsampleRatio420)
+	for i := range img.Y {
+		img.Y[i] = uint8(i * 30)
+	}
+	for _, premultiplied := range []bool{false, true} {
+		converted := alphaModeImage(img, premultiplied)
+		// What the loader hands to storagePixels is uploaded as it is
+		if again := alphaModeImage(converted, premultiplied); again != converted {
+			t.Errorf("premultiplied=%v: the %T is converted again into %T", premultiplied, converted, again)
+		}
+		texture := &Texture{format: gl.RGBA, pixelType: gl.UNSIGNED_BYTE, premultipliedAlpha: premultiplied}
+		pixels, _, _, err := texture.storagePixels(converted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, expected := (*[4 * 2 * 4]uint8)(pixels)[:], imagePix(converted); string(got) != string(expected) {
+			t.Errorf("premultiplied=%v: uploads %v, expected %v", premultiplied, got, expected)
+		}
+	}
+}
+
+func TestOversizeTextureIsRejected(t *testing.T) {
+	// A cached limit keeps MaxTextureSize from querying the context
+	defer func(previous int32) { maxTextureSize = previous }(maxTextureSize)
+	maxTextureSize = 1024
+
+	if err := checkTextureSize(1024, 1024); err != nil {
+		t.Errorf("1024x1024: unexpected error %v", err)
+	}
+	if err := checkTextureSize(16, 1025); err == nil {
+		t.Error("16x1025: expected an error")
+	}
+	// The size is checked before any OpenGL call
+	if _, err := NewTextureFromImage(image.NewRGBA(image.Rect(0, 0, 2048, 16))); err == nil {
+		t.Error("NewTextureFromImage 2048x16: expected an error")
+	}
+	if _, err := NewEmptyTexture(16, 4096, 0, 0, 0); err == nil {
+		t.Error("NewEmptyTexture 16x4096: expected an error")
+	}
+}
+
+func TestSubImageChecksTheTexture(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	// The checks happen before any OpenGL call
+	tests := []struct {
+		name    string
+		texture *Texture
+	}{
+		{"deleted", &Texture{target: gl.TEXTURE_2D, width: 4, height: 4, format: gl.RGBA, pixelType: gl.UNSIGNED_BYTE}},
+		{"array", &Texture{id: 1, target: gl.TEXTURE_2D_ARRAY, width: 4, height: 4, format: gl.RGBA}},
+		{"cubemap", &Texture{id: 1, target: gl.TEXTURE_CUBE_MAP, width: 4, height: 4, format: gl.RGBA}},
+		{"compressed", &Texture{id: 1, target: gl.TEXTURE_2D, width: 4, height: 4}},
+	}
+	for _, test := range tests {
+		if err := test.texture.SubImage(0, 0, img); err == nil {
+			t.Errorf("%s texture: expected an error", test.name)
+		}
+	}
+}
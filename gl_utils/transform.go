@@ -0,0 +1,106 @@
+package gl_utils
+
+import (
+	"errors"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Transform the position, rotation and scale of an object, optionally relative to a parent transform.
+// The local matrix is cached and rebuilt only after one of the setters has been called.
+// The zero value is an identity transform, like the one returned by NewTransform
+type Transform struct {
+	position mgl32.Vec3
+	// The zero quaternion, which SetRotation never stores, stands for no rotation
+	rotation mgl32.Quat
+	scale    mgl32.Vec3
+	// Whether SetScale has been called, until then the scale is 1 on the three axes
+	hasScale bool
+	parent   *Transform
+
+	matrix      mgl32.Mat4
+	matrixValid bool
+}
+
+// NewTransform creates an identity transform: at the origin, not rotated and with scale 1
+func NewTransform() *Transform {
+	return &Transform{}
+}
+
+// Position returns the position relative to the parent
+func (t *Transform) Position() mgl32.Vec3 {
+	return t.position
+}
+
+// SetPosition sets the position relative to the parent
+func (t *Transform) SetPosition(position mgl32.Vec3) {
+	t.position = position
+	t.matrixValid = false
+}
+
+// Rotation returns the rotation relative to the parent
+func (t *Transform) Rotation() mgl32.Quat {
+	if t.rotation == (mgl32.Quat{}) {
+		return mgl32.QuatIdent()
+	}
+	return t.rotation
+}
+
+// SetRotation sets the rotation relative to the parent. The quaternion is normalized
+func (t *Transform) SetRotation(rotation mgl32.Quat) {
+	t.rotation = rotation.Normalize()
+	t.matrixValid = false
+}
+
+// Scale returns the scale on the three axes
+func (t *Transform) Scale() mgl32.Vec3 {
+	if !t.hasScale {
+		return mgl32.Vec3{1, 1, 1}
+	}
+	return t.scale
+}
+
+// SetScale sets the scale on the three axes
+func (t *Transform) SetScale(scale mgl32.Vec3) {
+	t.scale = scale
+	t.hasScale = true
+	t.matrixValid = false
+}
+
+// Parent returns the parent transform, nil for a root
+func (t *Transform) Parent() *Transform {
+	return t.parent
+}
+
+// SetParent makes the transform relative to parent, nil makes it a root again. It fails if parent is the transform
+// itself or one of its descendants, which would create a loop
+func (t *Transform) SetParent(parent *Transform) error {
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == t {
+			return errors.New("setting the parent: the transform would be its own ancestor")
+		}
+	}
+	t.parent = parent
+	return nil
+}
+
+// Matrix returns the local model matrix, translation * rotation * scale, so the scale is applied first
+func (t *Transform) Matrix() mgl32.Mat4 {
+	if !t.matrixValid {
+		scale := t.Scale()
+		t.matrix = mgl32.Translate3D(t.position.X(), t.position.Y(), t.position.Z()).
+			Mul4(t.Rotation().Mat4()).
+			Mul4(mgl32.Scale3D(scale.X(), scale.Y(), scale.Z()))
+		t.matrixValid = true
+	}
+	return t.matrix
+}
+
+// WorldMatrix returns the model matrix in world space, the local matrix multiplied by the ones of all the ancestors.
+// Only the local matrices are cached, so that changing an ancestor is always reflected
+func (t *Transform) WorldMatrix() mgl32.Mat4 {
+	if t.parent == nil {
+		return t.Matrix()
+	}
+	return t.parent.WorldMatrix().Mul4(t.Matrix())
+}
@@ -0,0 +1,71 @@
+package gl_utils
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// SpriteSheetUVs returns the UV rectangle (u0, v0, u1, v1) of every frame of a sprite sheet where the frames are laid
+// out in a regular grid, in row-major order starting from the top-left frame. V follows the rows of the image, like
+// the textures uploaded without FlipVertically. Incomplete frames at the right and bottom edges are skipped
+func SpriteSheetUVs(sheetWidth, sheetHeight, frameWidth, frameHeight int) []mgl32.Vec4 {
+	return SpriteSheetUVsWithSpacing(sheetWidth, sheetHeight, frameWidth, frameHeight, 0, 0)
+}
+
+// SpriteSheetUVsWithSpacing is like SpriteSheetUVs for sheets with a margin of pixels around the grid and spacing
+// pixels between two frames
+func SpriteSheetUVsWithSpacing(sheetWidth, sheetHeight, frameWidth, frameHeight, margin, spacing int) []mgl32.Vec4 {
+	if sheetWidth <= 0 || sheetHeight <= 0 || frameWidth <= 0 || frameHeight <= 0 {
+		return nil
+	}
+	cols := (sheetWidth - 2*margin + spacing) / (frameWidth + spacing)
+	rows := (sheetHeight - 2*margin + spacing) / (frameHeight + spacing)
+	if cols <= 0 || rows <= 0 {
+		return nil
+	}
+	uvs := make([]mgl32.Vec4, 0, cols*rows)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x := margin + col*(frameWidth+spacing)
+			y := margin + row*(frameHeight+spacing)
+			uvs = append(uvs, mgl32.Vec4{
+				float32(x) / float32(sheetWidth),
+				float32(y) / float32(sheetHeight),
+				float32(x+frameWidth) / float32(sheetWidth),
+				float32(y+frameHeight) / float32(sheetHeight),
+			})
+		}
+	}
+	return uvs
+}
+
+// FitUVs returns the UV rectangle to map on a quad to show the whole texture without distortion (letterboxing).
+// On the axis where the quad is relatively longer the range goes beyond [0,1], use the gl.CLAMP_TO_BORDER wrap mode
+// to get empty bars there. Non positive sizes return the full texture
+func FitUVs(texW, texH, quadW, quadH float32) (uvMin, uvMax mgl32.Vec2) {
+	if texW <= 0 || texH <= 0 || quadW <= 0 || quadH <= 0 {
+		return mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}
+	}
+	texAspect := texW / texH
+	quadAspect := quadW / quadH
+	if quadAspect > texAspect {
+		return centeredUVRange(quadAspect/texAspect, 1)
+	}
+	return centeredUVRange(1, texAspect/quadAspect)
+}
+
+// CoverUVs returns the UV rectangle to map on a quad to fill it with the texture without distortion, cropping the
+// texture evenly on the axis where it's relatively longer. Non positive sizes return the full texture
+func CoverUVs(texW, texH, quadW, quadH float32) (uvMin, uvMax mgl32.Vec2) {
+	if texW <= 0 || texH <= 0 || quadW <= 0 || quadH <= 0 {
+		return mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}
+	}
+	texAspect := texW / texH
+	quadAspect := quadW / quadH
+	if quadAspect > texAspect {
+		return centeredUVRange(1, texAspect/quadAspect)
+	}
+	return centeredUVRange(quadAspect/texAspect, 1)
+}
+
+// centeredUVRange returns a UV rectangle of the given size centered on 0.5,0.5
+func centeredUVRange(sizeU, sizeV float32) (uvMin, uvMax mgl32.Vec2) {
+	return mgl32.Vec2{(1 - sizeU) / 2, (1 - sizeV) / 2}, mgl32.Vec2{(1 + sizeU) / 2, (1 + sizeV) / 2}
+}
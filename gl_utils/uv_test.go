@@ -0,0 +1,46 @@
+package gl_utils
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestFitAndCoverUVs(t *testing.T) {
+	tests := []struct {
+		name                               string
+		texW, texH, quadW, quadH           float32
+		fitMin, fitMax, coverMin, coverMax mgl32.Vec2
+	}{
+		{
+			"portrait into landscape", 500, 1000, 1000, 500,
+			mgl32.Vec2{-1.5, 0}, mgl32.Vec2{2.5, 1}, mgl32.Vec2{0, 0.375}, mgl32.Vec2{1, 0.625},
+		},
+		{
+			"landscape into portrait", 1000, 500, 500, 1000,
+			mgl32.Vec2{0, -1.5}, mgl32.Vec2{1, 2.5}, mgl32.Vec2{0.375, 0}, mgl32.Vec2{0.625, 1},
+		},
+		{
+			"square into square", 256, 256, 64, 64,
+			mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}, mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1},
+		},
+		{
+			"square into landscape", 256, 256, 200, 100,
+			mgl32.Vec2{-0.5, 0}, mgl32.Vec2{1.5, 1}, mgl32.Vec2{0, 0.25}, mgl32.Vec2{1, 0.75},
+		},
+		{
+			"invalid size", 0, 256, 200, 100,
+			mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}, mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1},
+		},
+	}
+	for _, test := range tests {
+		uvMin, uvMax := FitUVs(test.texW, test.texH, test.quadW, test.quadH)
+		if !uvMin.ApproxEqual(test.fitMin) || !uvMax.ApproxEqual(test.fitMax) {
+			t.Errorf("%s: FitUVs = %v %v, expected %v %v", test.name, uvMin, uvMax, test.fitMin, test.fitMax)
+		}
+		uvMin, uvMax = CoverUVs(test.texW, test.texH, test.quadW, test.quadH)
+		if !uvMin.ApproxEqual(test.coverMin) || !uvMax.ApproxEqual(test.coverMax) {
+			t.Errorf("%s: CoverUVs = %v %v, expected %v %v", test.name, uvMin, uvMax, test.coverMin, test.coverMax)
+		}
+	}
+}